@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/atlet99/pscloud-exporter/internal/client"
+	"github.com/atlet99/pscloud-exporter/internal/collector"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// dashboardSection groups metric families into a dashboard row
+type dashboardSection struct {
+	title    string
+	prefixes []string
+	suffixes []string
+}
+
+// dashboardSections lists the dashboard rows in display order
+var dashboardSections = []dashboardSection{
+	{title: "Balance", prefixes: []string{"pskz_account_"}, suffixes: []string{"_balance"}},
+	{title: "Domains", prefixes: []string{"pskz_domain_", "pskz_dns_"}},
+	{title: "Kubernetes", prefixes: []string{"pskz_k8s_"}},
+	{title: "Load Balancers", prefixes: []string{"pskz_lbaas_"}},
+	{title: "VPS", prefixes: []string{"pskz_vps_", "pskz_server_"}},
+	{title: "Cloud", prefixes: []string{"pskz_cloud_", "pskz_floating_ip_", "pskz_project_"}},
+}
+
+// descHelpRe and descLabelsRe extract the help text and variable labels from a Desc string
+var (
+	descHelpRe   = regexp.MustCompile(`help: ("(?:[^"\\]|\\.)*")`)
+	descLabelsRe = regexp.MustCompile(`variableLabels: \{([^}]*)\}`)
+)
+
+// dashboardMetric is a metric family shown as a dashboard panel
+type dashboardMetric struct {
+	name   string
+	help   string
+	labels []string
+}
+
+// section returns the title of the dashboard row the metric belongs to, or "" if none
+func (m dashboardMetric) section() string {
+	for _, section := range dashboardSections {
+		for _, prefix := range section.prefixes {
+			if strings.HasPrefix(m.name, prefix) {
+				return section.title
+			}
+		}
+		for _, suffix := range section.suffixes {
+			if strings.HasSuffix(m.name, suffix) {
+				return section.title
+			}
+		}
+	}
+	return ""
+}
+
+// describeMetrics returns the metric families described by c, sorted by name
+func describeMetrics(c prometheus.Collector) []dashboardMetric {
+	ch := make(chan *prometheus.Desc)
+	go func() {
+		c.Describe(ch)
+		close(ch)
+	}()
+
+	var metrics []dashboardMetric
+	for desc := range ch {
+		metric := dashboardMetric{name: collector.MetricName(desc)}
+		if match := descHelpRe.FindStringSubmatch(desc.String()); match != nil {
+			metric.help, _ = strconv.Unquote(match[1])
+		}
+		if match := descLabelsRe.FindStringSubmatch(desc.String()); match != nil && match[1] != "" {
+			metric.labels = strings.Split(match[1], ",")
+		}
+		metrics = append(metrics, metric)
+	}
+
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].name < metrics[j].name })
+	return metrics
+}
+
+// buildDashboard creates a Grafana dashboard with one row per section and one panel per metric family.
+// Info metrics only carry labels and are left out.
+func buildDashboard(metrics []dashboardMetric, title string) map[string]interface{} {
+	datasource := map[string]interface{}{"type": "prometheus", "uid": "${datasource}"}
+
+	var panels []interface{}
+	id, y := 1, 0
+	for _, section := range dashboardSections {
+		var sectionMetrics []dashboardMetric
+		for _, metric := range metrics {
+			if metric.section() == section.title && !strings.HasSuffix(metric.name, "_info") {
+				sectionMetrics = append(sectionMetrics, metric)
+			}
+		}
+		if len(sectionMetrics) == 0 {
+			continue
+		}
+
+		panels = append(panels, map[string]interface{}{
+			"id":        id,
+			"type":      "row",
+			"title":     section.title,
+			"collapsed": false,
+			"gridPos":   map[string]int{"h": 1, "w": 24, "x": 0, "y": y},
+			"panels":    []interface{}{},
+		})
+		id++
+		y++
+
+		for i, metric := range sectionMetrics {
+			legend := make([]string, 0, len(metric.labels))
+			for _, label := range metric.labels {
+				legend = append(legend, "{{"+label+"}}")
+			}
+
+			panelTitle := metric.help
+			if panelTitle == "" {
+				panelTitle = metric.name
+			}
+
+			panels = append(panels, map[string]interface{}{
+				"id":          id,
+				"type":        "timeseries",
+				"title":       panelTitle,
+				"description": metric.name,
+				"datasource":  datasource,
+				"gridPos":     map[string]int{"h": 8, "w": 12, "x": (i % 2) * 12, "y": y + (i/2)*8},
+				"targets": []interface{}{
+					map[string]interface{}{
+						"datasource":   datasource,
+						"expr":         metric.name,
+						"legendFormat": strings.Join(legend, " "),
+						"refId":        "A",
+					},
+				},
+			})
+			id++
+		}
+		y += ((len(sectionMetrics) + 1) / 2) * 8
+	}
+
+	return map[string]interface{}{
+		"title":         title,
+		"uid":           "pscloud-exporter",
+		"tags":          []string{"pscloud", "ps.kz"},
+		"schemaVersion": 39,
+		"editable":      true,
+		"refresh":       "1m",
+		"time":          map[string]string{"from": "now-24h", "to": "now"},
+		"templating": map[string]interface{}{
+			"list": []interface{}{
+				map[string]interface{}{
+					"name":  "datasource",
+					"label": "Data source",
+					"type":  "datasource",
+					"query": "prometheus",
+				},
+			},
+		},
+		"panels": panels,
+	}
+}
+
+// writeDashboard writes the dashboard for the exporter's metrics as indented JSON
+func writeDashboard(c prometheus.Collector, title string, w io.Writer) error {
+	data, err := json.MarshalIndent(buildDashboard(describeMetrics(c), title), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// runDashboardCommand implements the dashboard subcommand. It works offline:
+// the panels are generated from the metric descriptors without querying the API.
+func runDashboardCommand(args []string) error {
+	fs := flag.NewFlagSet("dashboard", flag.ExitOnError)
+	output := fs.String("output", "", "File to write the dashboard JSON to (default: stdout)")
+	title := fs.String("title", "PS.KZ Cloud", "Dashboard title")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	exporter := collector.New(client.New(""), "")
+
+	if *output == "" {
+		return writeDashboard(exporter, *title, os.Stdout)
+	}
+
+	f, err := os.Create(*output)
+	if err != nil {
+		return fmt.Errorf("failed to create dashboard file: %w", err)
+	}
+	if err := writeDashboard(exporter, *title, f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
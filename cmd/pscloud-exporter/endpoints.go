@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/atlet99/pscloud-exporter/internal/client"
+)
+
+// reportEndpoints checks every GraphQL service and prints a reachability table.
+// It only reports problems and never fails, so a partial outage does not prevent startup.
+func reportEndpoints(c *client.Client, w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SERVICE\tSTATUS\tLATENCY\tDETAILS")
+	for _, status := range c.VerifyEndpoints() {
+		if status.Disabled {
+			fmt.Fprintf(tw, "%s\tdisabled\t-\t\n", status.Name)
+			continue
+		}
+		state, details := "ok", ""
+		if status.Err != nil {
+			state, details = "error", status.Err.Error()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", status.Name, state, status.Duration.Round(time.Millisecond), details)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+)
+
+// logRing keeps the most recent log lines in memory so they can be read over HTTP.
+// It is added as a writer of the standard logger, which the collector logs through.
+type logRing struct {
+	mutex   sync.Mutex
+	lines   []string
+	next    int          // index the next line is written to once the ring is full
+	partial bytes.Buffer // text after the last newline, kept until the line is complete
+}
+
+// newLogRing creates a ring that holds up to size lines
+func newLogRing(size int) *logRing {
+	return &logRing{lines: make([]string, 0, size)}
+}
+
+// Write stores every complete line of p, dropping the oldest lines once the ring is full
+func (r *logRing) Write(p []byte) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.partial.Write(p)
+	for {
+		line, err := r.partial.ReadString('\n')
+		if err != nil {
+			// Keep the incomplete line for the next write
+			r.partial.Reset()
+			r.partial.WriteString(line)
+			break
+		}
+		r.add(line)
+	}
+	return len(p), nil
+}
+
+// add appends line, overwriting the oldest line if the ring is full
+func (r *logRing) add(line string) {
+	if len(r.lines) < cap(r.lines) {
+		r.lines = append(r.lines, line)
+		return
+	}
+	r.lines[r.next] = line
+	r.next = (r.next + 1) % len(r.lines)
+}
+
+// Lines returns the stored lines from oldest to newest
+func (r *logRing) Lines() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	lines := make([]string, 0, len(r.lines))
+	lines = append(lines, r.lines[r.next:]...)
+	return append(lines, r.lines[:r.next]...)
+}
+
+// ServeHTTP writes the stored lines as plain text, oldest first
+func (r *logRing) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	for _, line := range r.Lines() {
+		if _, err := w.Write([]byte(line)); err != nil {
+			return
+		}
+	}
+}
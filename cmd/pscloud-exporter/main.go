@@ -2,12 +2,16 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -16,6 +20,7 @@ import (
 	"github.com/atlet99/pscloud-exporter/internal/config"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"gopkg.in/yaml.v3"
 )
 
 var (
@@ -31,6 +36,20 @@ func displayVersion() {
 	fmt.Printf("Build: %s\n", Build)
 }
 
+// stringList is a flag value that may be given multiple times
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// findConfigFile resolves the configuration file path.
+// Precedence: -config flag > PSCLOUD_CONFIG_FILE environment variable > config.yml/config.yaml in the working directory
 func findConfigFile(configPath string) (string, error) {
 	// If path is explicitly specified, check its existence
 	if configPath != "" {
@@ -40,6 +59,14 @@ func findConfigFile(configPath string) (string, error) {
 		return "", fmt.Errorf("config file not found: %s", configPath)
 	}
 
+	// Fall back to the path from the environment
+	if envPath := os.Getenv("PSCLOUD_CONFIG_FILE"); envPath != "" {
+		if _, err := os.Stat(envPath); err == nil {
+			return envPath, nil
+		}
+		return "", fmt.Errorf("config file from PSCLOUD_CONFIG_FILE not found: %s", envPath)
+	}
+
 	// Check both extension variants
 	configFiles := []string{"config.yml", "config.yaml"}
 	for _, file := range configFiles {
@@ -51,6 +78,226 @@ func findConfigFile(configPath string) (string, error) {
 	return "", fmt.Errorf("no config file found. Please create either config.yml or config.yaml")
 }
 
+// validateListenNetwork checks that the network is supported by listen
+func validateListenNetwork(network string) error {
+	switch network {
+	case "", "tcp", "tcp4", "tcp6":
+		return nil
+	}
+	return fmt.Errorf("unsupported listen network %q (expected tcp, tcp4 or tcp6)", network)
+}
+
+// listen opens the listener for the HTTP server on the given network.
+// Bracketed IPv6 literals such as [::1]:9116 are accepted as the address.
+func listen(network, address string) (net.Listener, error) {
+	if err := validateListenNetwork(network); err != nil {
+		return nil, err
+	}
+	if network == "" {
+		network = "tcp"
+	}
+
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return nil, fmt.Errorf("invalid listen address %q: %w", address, err)
+	}
+
+	return net.Listen(network, address)
+}
+
+// metricsHandler serves the metrics of reg.
+// It negotiates gzip via Accept-Encoding unless compression is disabled.
+func metricsHandler(reg *prometheus.Registry, disableCompression bool) http.Handler {
+	return promhttp.HandlerFor(reg, promhttp.HandlerOpts{
+		DisableCompression: disableCompression,
+	})
+}
+
+// startHTTPServer serves the metrics and landing page in the background
+// status is served at /status and logs at /logs unless they are nil.
+func startHTTPServer(reg *prometheus.Registry, network, address, metricsPath string, disableCompression bool, readyz, status, logs http.Handler) *http.Server {
+	http.Handle(metricsPath, metricsHandler(reg, disableCompression))
+	http.Handle("/readyz", readyz)
+	statusLink := ""
+	if status != nil {
+		http.Handle("/status", status)
+		statusLink = `<p><a href="/status">Status</a></p>`
+	}
+	logsLink := ""
+	if logs != nil {
+		http.Handle("/logs", logs)
+		logsLink = `<p><a href="/logs">Recent logs</a></p>`
+	}
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`<html>
+			<head><title>PSCloud Exporter</title></head>
+			<body>
+			<h1>PSCloud Exporter</h1>
+			<p><a href="` + metricsPath + `">Metrics</a></p>
+			` + statusLink + `
+			` + logsLink + `
+			<p>Version: ` + Version + `</p>
+			<p>Build: ` + Build + `</p>
+			</body>
+			</html>`))
+		if err != nil {
+			log.Printf("Error writing response: %v", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+	})
+
+	srv := &http.Server{
+		Addr: address,
+	}
+
+	listener, err := listen(network, address)
+	if err != nil {
+		log.Fatalf("Error starting HTTP server: %s", err)
+	}
+
+	// Graceful shutdown
+	go func() {
+		if err := srv.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Error starting HTTP server: %s", err)
+		}
+	}()
+
+	log.Printf("Server listening on %s (%s)", listener.Addr(), network)
+	return srv
+}
+
+// readinessHandler reports ready unless scrapes have kept failing for longer than grace.
+// Before the first scrape and while failures are within the grace period it reports ready.
+func readinessHandler(exporter *collector.Exporter, grace time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if since := exporter.FailingSince(); !since.IsZero() && time.Since(since) > grace {
+			http.Error(w, fmt.Sprintf("scrapes failing since %s", since.Format(time.RFC3339)), http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok\n"))
+	})
+}
+
+// statusHandler serves the account summary of the latest scrape as JSON.
+// It does not trigger a scrape and reports 503 until the first one has run.
+func statusHandler(exporter *collector.Exporter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status, ok := exporter.Status()
+		if !ok {
+			http.Error(w, "no scrape has run yet", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(status); err != nil {
+			log.Printf("Error writing status: %v", err)
+		}
+	})
+}
+
+// validateConfig checks the settings that do not require contacting the API
+func validateConfig(cfg *config.Config) error {
+	if err := client.ValidateAuthHeaderMode(cfg.AuthHeaderMode); err != nil {
+		return err
+	}
+	if err := validateListenNetwork(cfg.Web.ListenNetwork); err != nil {
+		return err
+	}
+	if err := collector.ValidateProjectLabel(cfg.ProjectLabel); err != nil {
+		return err
+	}
+	if err := collector.ValidateSuccessPolicy(cfg.SuccessPolicy); err != nil {
+		return err
+	}
+	if err := collector.ValidateRelabelRules(relabelRules(cfg.Relabel)); err != nil {
+		return err
+	}
+	if err := client.ValidateCloudInstanceFields(cfg.InstanceFields); err != nil {
+		return err
+	}
+	if err := client.ValidatePerPage(cfg.Pagination.PerPage); err != nil {
+		return err
+	}
+	if err := client.ValidateDisabledServices(cfg.Services.Disabled); err != nil {
+		return fmt.Errorf("services.disabled: %w", err)
+	}
+	for _, server := range vpsServerRefs(cfg.Monitor.VPSServers) {
+		if err := server.Validate(); err != nil {
+			return fmt.Errorf("monitor.vpsServers: %w", err)
+		}
+	}
+	for _, id := range cfg.Monitor.CloudInstances {
+		if strings.TrimSpace(id) == "" {
+			return fmt.Errorf("monitor.cloudInstances must not contain empty IDs")
+		}
+	}
+	for _, days := range cfg.Domains.ExpiryBuckets {
+		if days <= 0 {
+			return fmt.Errorf("domains.expiryBuckets must be positive numbers of days, got %d", days)
+		}
+	}
+	if cfg.CircuitBreaker.FailureThreshold == 0 {
+		return fmt.Errorf("circuitBreaker.failureThreshold must not be 0 (use a negative value to disable the circuit breaker)")
+	}
+	if cfg.CircuitBreaker.CooldownSeconds <= 0 {
+		return fmt.Errorf("circuitBreaker.cooldownSeconds must be positive, got %d", cfg.CircuitBreaker.CooldownSeconds)
+	}
+	if cfg.Web.ReadinessGraceSeconds < 0 {
+		return fmt.Errorf("web.readinessGraceSeconds must not be negative, got %d", cfg.Web.ReadinessGraceSeconds)
+	}
+	if cfg.TokenFile != "" {
+		if _, err := config.ReadTokenFile(cfg.TokenFile); err != nil {
+			return fmt.Errorf("error reading token file: %w", err)
+		}
+	}
+	return nil
+}
+
+// vpsServerRefs converts the monitored VPS servers of the configuration for the client
+func vpsServerRefs(servers []config.VPSServerConfig) []client.VPSServerRef {
+	refs := make([]client.VPSServerRef, 0, len(servers))
+	for _, server := range servers {
+		refs = append(refs, client.VPSServerRef{ServerID: server.ServerID, RegionID: server.RegionID})
+	}
+	return refs
+}
+
+// relabelRules converts the configured relabel rules to collector rules
+func relabelRules(rules []config.RelabelConfig) []collector.RelabelRule {
+	converted := make([]collector.RelabelRule, 0, len(rules))
+	for _, rule := range rules {
+		converted = append(converted, collector.RelabelRule{Source: rule.Source, Target: rule.Target, Action: rule.Action})
+	}
+	return converted
+}
+
+// warnPerPageRange logs a warning if a configured page size will be clamped
+func warnPerPageRange(setting string, size int) {
+	if size != 0 && (size < client.MinPerPage || size > client.MaxPerPage) {
+		log.Printf("Warning: %s=%d is outside %d..%d and will be clamped", setting, size, client.MinPerPage, client.MaxPerPage)
+	}
+}
+
+// printConfig writes the effective configuration as YAML with the token redacted,
+// preceded by a comment naming the source of the token
+func printConfig(cfg *config.Config, w io.Writer) error {
+	redacted := *cfg
+	if redacted.Token != "" {
+		redacted.Token = "<redacted>"
+	}
+
+	data, err := yaml.Marshal(&redacted)
+	if err != nil {
+		return err
+	}
+	if cfg.TokenSource != "" {
+		if _, err := fmt.Fprintf(w, "# token source: %s\n", cfg.TokenSource); err != nil {
+			return err
+		}
+	}
+	_, err = w.Write(data)
+	return err
+}
+
 // validateAuth attempts to validate the API token by making a test API call
 func validateAuth(c *client.Client) error {
 	log.Println("Validating API token...")
@@ -64,19 +311,99 @@ func validateAuth(c *client.Client) error {
 	return nil
 }
 
+// Modes of the startup authentication check
+const (
+	authCheckFatal = "fatal"
+	authCheckWarn  = "warn"
+	authCheckRetry = "retry"
+)
+
+// Backoff between startup authentication attempts in retry mode
+const (
+	authRetryInitialBackoff = 5 * time.Second
+	authRetryMaxBackoff     = 5 * time.Minute
+)
+
+// validateAuthCheckMode checks that mode is a supported startup authentication check mode
+func validateAuthCheckMode(mode string) error {
+	switch mode {
+	case authCheckFatal, authCheckWarn, authCheckRetry:
+		return nil
+	}
+	return fmt.Errorf("unsupported auth check mode %q (expected %s, %s or %s)", mode, authCheckFatal, authCheckWarn, authCheckRetry)
+}
+
+// checkAuth validates the API token on startup. On failure, fatal mode exits,
+// warn mode logs the failure and returns so the exporter serves anyway, and
+// retry mode tries again with exponential backoff until the check succeeds.
+func checkAuth(c *client.Client, mode string) {
+	backoff := authRetryInitialBackoff
+	for {
+		err := validateAuth(c)
+		if err == nil {
+			return
+		}
+
+		switch mode {
+		case authCheckWarn:
+			log.Printf("Warning: %v; starting anyway, scrapes will report the failure", err)
+			return
+		case authCheckRetry:
+			log.Printf("%v; retrying in %s", err, backoff)
+			time.Sleep(backoff)
+			backoff = min(backoff*2, authRetryMaxBackoff)
+		default:
+			log.Fatal(err)
+		}
+	}
+}
+
 func main() {
 	// Variable declarations
 	var (
 		listenAddress = flag.String("listen-address", ":9116", "Address to listen on for web interface and telemetry.")
+		listenNetwork = flag.String("listen-network", "", "Network to listen on: tcp, tcp4 or tcp6 (default: from config, or tcp)")
 		metricsPath   = flag.String("metrics-path", "/metrics", "Path under which to expose metrics.")
 		configFile    = flag.String("config", "", "Path to configuration file (supports .yml or .yaml)")
 		token         = flag.String("token", "", "PS.KZ API token")
 		serviceID     = flag.String("service-id", "", "PS.KZ service ID for cloud servers")
 		baseURL       = flag.String("base-url", "", "Base URL for PS.KZ API (default: https://console.ps.kz)")
+		localAddress  = flag.String("local-address", "", "Source IP address to send PS.KZ API requests from (default: chosen by the system)")
 		skipAuth      = flag.Bool("skip-auth-check", false, "Skip authentication validation on startup")
+		authCheckMode = flag.String("auth-check-mode", authCheckFatal, "What a failed startup authentication check does: fatal exits, warn logs it and starts serving, retry backs off until it succeeds")
+		verifyAll     = flag.Bool("verify-endpoints", false, "Check connectivity to every PS.KZ GraphQL service on startup and report it without failing")
+		noCompression = flag.Bool("disable-compression", false, "Disable gzip compression of the metrics response even if the client accepts it")
+		statusPage    = flag.Bool("status-page", false, "Serve a JSON summary of the account from the latest scrape without authentication at /status")
+		logLines      = flag.Int("log-buffer-lines", 0, "Number of recent log lines kept in memory and served without authentication at /logs (0 disables)")
 		showVersion   = flag.Bool("version", false, "Show version information and exit")
+		checkConfig   = flag.Bool("check-config", false, "Validate the configuration and exit; no token is required")
+		printCfg      = flag.Bool("print-config", false, "Print the effective configuration with the token redacted and exit; no token is required")
+		selfCheck     = flag.Bool("self-check", false, "Run a single scrape, report which metrics are populated and exit")
+		once          = flag.Bool("once", false, "Run a single scrape, print the metrics to stdout and exit with status 0 on success or 1 on failure")
+		textfilePath  = flag.String("textfile-output", "", "Write metrics to this file on an interval (node_exporter textfile collector style) instead of serving HTTP")
+		textfileEvery = flag.Duration("textfile-interval", time.Minute, "Interval between textfile writes")
+		textfileJit   = flag.Float64("textfile-jitter", 0.1, "Random extra delay of up to this fraction of -textfile-interval added to every interval (0 disables)")
+		textfileMaxBo = flag.Duration("textfile-max-backoff", 10*time.Minute, "Longest interval between textfile writes while scrapes keep failing")
+		textfileHTTP  = flag.Bool("textfile-serve-http", false, "Keep serving HTTP when -textfile-output is used")
+		pushURL       = flag.String("push.url", "", "Push metrics to this Pushgateway-compatible URL on an interval instead of serving HTTP")
+		pushJob       = flag.String("push.job", "pscloud_exporter", "Job name the metrics are pushed under")
+		pushEvery     = flag.Duration("push.interval", time.Minute, "Interval between pushes")
+		pushHTTP      = flag.Bool("push.serve-http", false, "Keep serving HTTP when -push.url is used")
+		deltaExpose   = flag.Bool("experimental-delta-exposition", false, "Keep series of data groups whose API response is unchanged instead of recomputing them (experimental)")
+		onlyCollector = flag.String("collector.only", "", "Scrape only this collector, overriding the collectors config, for debugging (one of "+strings.Join(collector.CollectorNames, ", ")+")")
 	)
 
+	// Subcommands are handled before the exporter flags
+	if len(os.Args) > 1 && os.Args[1] == "dashboard" {
+		if err := runDashboardCommand(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		os.Exit(0)
+	}
+
+	var envFiles stringList
+	flag.Var(&envFiles, "env-file", "Path to a .env file to load; may be repeated, later files override earlier ones (default: PSCLOUD_ENV_FILE)")
+
 	flag.Parse()
 
 	// Show version and exit if requested
@@ -85,6 +412,17 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Keep recent log lines in memory if requested. This must happen before
+	// the exporter is created, as its logger writes to the standard log output.
+	var logs *logRing
+	if *logLines < 0 {
+		log.Fatal("-log-buffer-lines must not be negative")
+	}
+	if *logLines > 0 {
+		logs = newLogRing(*logLines)
+		log.SetOutput(io.MultiWriter(log.Writer(), logs))
+	}
+
 	// Find configuration file
 	configPath, err := findConfigFile(*configFile)
 	if err != nil {
@@ -93,21 +431,77 @@ func main() {
 
 	log.Printf("Using config file: %s", configPath)
 
-	cfg, err := config.LoadConfig(configPath)
+	cfg, err := config.LoadConfig(configPath, envFiles...)
 	if err != nil {
 		log.Fatal(err)
 	}
+	configLoadedAt := time.Now()
 
 	// Command line arguments take priority
 	if *token != "" {
 		cfg.Token = *token
+		cfg.TokenSource = config.TokenSourceFlag
+	}
+
+	// The token file is only read if no token was given directly
+	if cfg.Token == "" && cfg.TokenFile != "" {
+		cfg.TokenSource = config.TokenSourceFile
 	}
 
 	if *serviceID != "" {
 		cfg.ServiceID = *serviceID
 	}
 
-	// Check if token exists
+	if *listenNetwork != "" {
+		cfg.Web.ListenNetwork = *listenNetwork
+	}
+
+	if *baseURL != "" {
+		cfg.BaseURL = *baseURL
+	}
+
+	// Informational modes below do not need a token
+	if err := validateConfig(cfg); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := validateAuthCheckMode(*authCheckMode); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := client.ValidateLocalAddress(*localAddress); err != nil {
+		log.Fatal(err)
+	}
+
+	if *onlyCollector != "" {
+		if err := collector.ValidateCollectorName(*onlyCollector); err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Scraping only the %s collector", *onlyCollector)
+	}
+
+	if *checkConfig {
+		log.Println("Configuration is valid")
+		os.Exit(0)
+	}
+
+	if *printCfg {
+		if err := printConfig(cfg, os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		os.Exit(0)
+	}
+
+	// Read the token from the token file if none was given directly
+	if cfg.Token == "" && cfg.TokenFile != "" {
+		fileToken, err := config.ReadTokenFile(cfg.TokenFile)
+		if err != nil {
+			log.Fatalf("Error reading token file: %v", err)
+		}
+		cfg.Token = fileToken
+	}
+
+	// The token is only required once the API is actually used
 	if cfg.Token == "" {
 		log.Fatal("API token is required. Set it in config file or via -token flag.")
 	}
@@ -116,69 +510,178 @@ func main() {
 	clientOptions := client.ClientOptions{}
 
 	// Set base URL if provided
-	if *baseURL != "" {
-		clientOptions.BaseURL = *baseURL
-	} else if cfg.BaseURL != "" {
+	if cfg.BaseURL != "" {
 		clientOptions.BaseURL = cfg.BaseURL
 	}
 
+	// Set authentication header mode
+	clientOptions.AuthHeaderMode = cfg.AuthHeaderMode
+
+	// Set page sizes, the client clamps them to the range accepted by the API
+	clientOptions.DefaultPerPage = cfg.Pagination.DefaultPerPage
+	clientOptions.PerPage = cfg.Pagination.PerPage
+
+	// Skip endpoints that keep failing
+	clientOptions.CircuitFailureThreshold = cfg.CircuitBreaker.FailureThreshold
+	clientOptions.CircuitCooldown = time.Duration(cfg.CircuitBreaker.CooldownSeconds) * time.Second
+
+	// Never call services the token has no access to
+	clientOptions.DisabledServices = cfg.Services.Disabled
+
+	// Send requests from a specific source IP if requested
+	clientOptions.LocalAddress = *localAddress
+	warnPerPageRange("pagination.defaultPerPage", cfg.Pagination.DefaultPerPage)
+	for name, size := range cfg.Pagination.PerPage {
+		warnPerPageRange("pagination.perPage."+name, size)
+	}
+
+	// Re-read the token file when the API rejects the current token
+	if cfg.TokenFile != "" {
+		tokenFile := cfg.TokenFile
+		clientOptions.TokenProvider = func() (string, error) {
+			return config.ReadTokenFile(tokenFile)
+		}
+	}
+
 	// Create client with options
 	c := client.NewWithOptions(cfg.Token, clientOptions)
 
 	// Validate authentication unless skipped
 	if !*skipAuth {
-		if err := validateAuth(c); err != nil {
-			log.Fatal(err)
-		}
+		checkAuth(c, *authCheckMode)
+	}
+
+	// Report which GraphQL services are reachable
+	if *verifyAll {
+		reportEndpoints(c, os.Stderr)
 	}
 
 	// Create a new registry for our metrics
 	reg := prometheus.NewRegistry()
 
 	// Create and register our collector
-	exporter := collector.New(c, cfg.ServiceID)
+	exporter := collector.NewWithOptions(c, collector.Options{
+		ServiceID:           cfg.ServiceID,
+		DNSZones:            cfg.DNSZones,
+		DomainStatuses:      cfg.Domains.Statuses,
+		DomainExpiryBuckets: cfg.Domains.ExpiryBuckets,
+		CloudInstanceFields: cfg.InstanceFields,
+		VPSServers:          vpsServerRefs(cfg.Monitor.VPSServers),
+		CloudInstances:      cfg.Monitor.CloudInstances,
+		ProjectLabel:        cfg.ProjectLabel,
+		Debounce:            cfg.Debounce,
+		Relabel:             relabelRules(cfg.Relabel),
+		MaxSeriesPerMetric:  cfg.MaxSeries,
+		DropZeroSeries:      cfg.DropZeroSeries,
+		MetricTimestamps:    cfg.Timestamps,
+		ScrapeErrorWindow:   cfg.ErrorWindow,
+		SuccessPolicy:       cfg.SuccessPolicy,
+		AccountVerification: cfg.Collectors.AccountVerification,
+		BankCards:           cfg.Collectors.BankCards,
+		DomainPrices:        cfg.Collectors.DomainPrices,
+		DomainAutoRenew:     cfg.Collectors.DomainAutoRenew,
+		RegionNames:         cfg.RegionNames,
+		DeltaExposition:     *deltaExpose,
+		Only:                *onlyCollector,
+		ConfigLoadedAt:      configLoadedAt,
+		Version:             Version,
+		Build:               Build,
+		TokenSource:         cfg.TokenSource,
+	})
 	reg.MustRegister(exporter)
 
-	// Create handler for metrics with our registry
-	handler := promhttp.HandlerFor(reg, promhttp.HandlerOpts{})
-	http.Handle(*metricsPath, handler)
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		_, err := w.Write([]byte(`<html>
-			<head><title>PSCloud Exporter</title></head>
-			<body>
-			<h1>PSCloud Exporter</h1>
-			<p><a href="` + *metricsPath + `">Metrics</a></p>
-			<p>Version: ` + Version + `</p>
-			<p>Build: ` + Build + `</p>
-			</body>
-			</html>`))
+	// Report which metrics are populated and exit if requested
+	if *selfCheck {
+		if err := runSelfCheck(exporter, reg, os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		os.Exit(0)
+	}
+
+	// Print the metrics of a single scrape and exit with its status if requested
+	if *once {
+		success, err := runOnce(reg, os.Stdout)
 		if err != nil {
-			log.Printf("Error writing response: %v", err)
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-			return
+			log.Fatal(err)
 		}
-	})
+		if !success {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
 
-	srv := &http.Server{
-		Addr: *listenAddress,
+	// Stops the textfile writer and pusher on shutdown
+	backgroundStop := make(chan struct{})
+	serveHTTP := true
+
+	// Write metrics to a textfile if requested
+	if *textfilePath != "" {
+		if *textfileEvery <= 0 {
+			log.Fatal("-textfile-interval must be positive")
+		}
+		if *textfileJit < 0 || *textfileJit > 1 {
+			log.Fatal("-textfile-jitter must be between 0 and 1")
+		}
+		if *textfileMaxBo < *textfileEvery {
+			log.Fatal("-textfile-max-backoff must not be shorter than -textfile-interval")
+		}
+		backoffMetric := prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "pskz_refresh_backoff_seconds",
+			Help: "Extra delay added to the textfile write interval after consecutive failed scrapes",
+		})
+		reg.MustRegister(backoffMetric)
+		failing := func() bool { return !exporter.FailingSince().IsZero() }
+		go runTextfileWriter(reg, *textfilePath, *textfileEvery, *textfileJit, *textfileMaxBo, failing, backoffMetric, backgroundStop)
+		log.Printf("Writing metrics to %s every %s", *textfilePath, *textfileEvery)
+		serveHTTP = *textfileHTTP
 	}
 
-	// Graceful shutdown
-	go func() {
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Error starting HTTP server: %s", err)
+	// Push metrics to a Pushgateway if requested
+	if *pushURL != "" {
+		if *textfilePath != "" {
+			log.Fatal("-push.url cannot be combined with -textfile-output")
 		}
-	}()
+		if err := validatePushURL(*pushURL); err != nil {
+			log.Fatal(err)
+		}
+		if *pushJob == "" {
+			log.Fatal("-push.job must not be empty")
+		}
+		if *pushEvery <= 0 {
+			log.Fatal("-push.interval must be positive")
+		}
+		go runPusher(reg, *pushURL, *pushJob, *pushEvery, backgroundStop)
+		log.Printf("Pushing metrics to %s as job %q every %s", *pushURL, *pushJob, *pushEvery)
+		serveHTTP = *pushHTTP
+	}
 
-	log.Printf("Server listening on %s", *listenAddress)
+	var srv *http.Server
+	if serveHTTP {
+		grace := time.Duration(cfg.Web.ReadinessGraceSeconds) * time.Second
+		var status http.Handler
+		if *statusPage {
+			status = statusHandler(exporter)
+		}
+		var logsHandler http.Handler
+		if logs != nil {
+			logsHandler = logs
+		}
+		srv = startHTTPServer(reg, cfg.Web.ListenNetwork, *listenAddress, *metricsPath, *noCompression, readinessHandler(exporter, grace), status, logsHandler)
+	}
 
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 	<-stop
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if err := srv.Shutdown(ctx); err != nil {
-		log.Printf("Error shutting down HTTP server: %s", err)
+	close(backgroundStop)
+
+	if srv != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			log.Printf("Error shutting down HTTP server: %s", err)
+		}
 	}
+
+	c.Close()
 }
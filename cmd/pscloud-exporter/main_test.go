@@ -0,0 +1,103 @@
+package main
+
+import (
+	"compress/gzip"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestListenIPv6Loopback(t *testing.T) {
+	// Skip on hosts without IPv6, where the loopback address cannot be bound at all
+	probe, err := net.Listen("tcp6", "[::1]:0")
+	if err != nil {
+		t.Skipf("IPv6 loopback is not available: %v", err)
+	}
+	probe.Close()
+
+	listener, err := listen("tcp6", "[::1]:0")
+	if err != nil {
+		t.Fatalf("listen() error = %v", err)
+	}
+	defer listener.Close()
+
+	addr, ok := listener.Addr().(*net.TCPAddr)
+	if !ok || !addr.IP.Equal(net.IPv6loopback) || addr.Port == 0 {
+		t.Errorf("listening on %v, want [::1] with a port", listener.Addr())
+	}
+}
+
+func TestListenInvalidAddress(t *testing.T) {
+	if _, err := listen("tcp", "::1:9116"); err == nil || !strings.Contains(err.Error(), "invalid listen address") {
+		t.Errorf("listen() with an unbracketed IPv6 address error = %v, want an invalid address error", err)
+	}
+}
+
+func TestValidateListenNetwork(t *testing.T) {
+	for _, network := range []string{"", "tcp", "tcp4", "tcp6"} {
+		if err := validateListenNetwork(network); err != nil {
+			t.Errorf("validateListenNetwork(%q) = %v, want nil", network, err)
+		}
+	}
+	for _, network := range []string{"udp", "unix", "TCP"} {
+		if err := validateListenNetwork(network); err == nil {
+			t.Errorf("validateListenNetwork(%q) = nil, want an error", network)
+		}
+	}
+}
+
+func TestMetricsHandlerCompression(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "pskz_test_gauge", Help: "Test gauge"})
+	gauge.Set(1)
+	reg.MustRegister(gauge)
+
+	tests := []struct {
+		name               string
+		disableCompression bool
+		wantEncoding       string
+	}{
+		{"gzip negotiated", false, "gzip"},
+		{"compression disabled", true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(metricsHandler(reg, tt.disableCompression))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Setting the header stops the transport from decompressing the body itself
+			req.Header.Set("Accept-Encoding", "gzip")
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			if got := resp.Header.Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Errorf("Content-Encoding = %q, want %q", got, tt.wantEncoding)
+			}
+			var body io.Reader = resp.Body
+			if tt.wantEncoding == "gzip" {
+				if body, err = gzip.NewReader(resp.Body); err != nil {
+					t.Fatalf("invalid gzip body: %v", err)
+				}
+			}
+			content, err := io.ReadAll(body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(content), "pskz_test_gauge 1") {
+				t.Errorf("body does not contain the metric:\n%s", content)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// runOnce performs a single scrape and writes the metrics to w in the Prometheus text format.
+// It returns whether the scrape succeeded according to pskz_scrape_success.
+func runOnce(gatherer prometheus.Gatherer, w io.Writer) (bool, error) {
+	families, err := gatherer.Gather()
+	if err != nil {
+		return false, fmt.Errorf("failed to gather metrics: %w", err)
+	}
+
+	if err := encodeMetrics(w, families); err != nil {
+		return false, err
+	}
+
+	for _, family := range families {
+		if family.GetName() != "pskz_scrape_success" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			if metric.GetGauge().GetValue() == 1 {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// validatePushURL checks that rawURL is an absolute http or https URL
+func validatePushURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid push URL %q: %w", rawURL, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid push URL %q: must be an http or https URL", rawURL)
+	}
+	return nil
+}
+
+// runPusher sends metrics to the Pushgateway at pushURL under job immediately and
+// then on every interval until stop is closed. Metrics are sent with POST, which
+// replaces the pushed metric families of the job and keeps all others.
+func runPusher(gatherer prometheus.Gatherer, pushURL, job string, interval time.Duration, stop <-chan struct{}) {
+	pusher := push.New(pushURL, job).Gatherer(gatherer)
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	failing := false
+	for {
+		select {
+		case <-timer.C:
+		case <-stop:
+			return
+		}
+
+		if err := pusher.Add(); err != nil {
+			log.Printf("Error pushing metrics to %s: %v", pushURL, err)
+			failing = true
+		} else if failing {
+			log.Printf("Pushing metrics to %s succeeded again", pushURL)
+			failing = false
+		}
+
+		timer.Reset(interval)
+	}
+}
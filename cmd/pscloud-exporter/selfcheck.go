@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/atlet99/pscloud-exporter/internal/collector"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// runSelfCheck performs a single scrape and reports, per metric family,
+// how many series were produced and whether the data source is real or a stub
+func runSelfCheck(exporter prometheus.Collector, reg *prometheus.Registry, w io.Writer) error {
+	series := make(map[string]int)
+
+	// Start from every described metric so that empty families are reported too
+	descs := make(chan *prometheus.Desc)
+	go func() {
+		exporter.Describe(descs)
+		close(descs)
+	}()
+	for desc := range descs {
+		if name := collector.MetricName(desc); name != "" {
+			series[name] = 0
+		}
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		return fmt.Errorf("failed to gather metrics: %w", err)
+	}
+	for _, family := range families {
+		series[family.GetName()] = len(family.GetMetric())
+	}
+
+	names := make([]string, 0, len(series))
+	for name := range series {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "METRIC\tSERIES\tPOPULATED\tSOURCE")
+	for _, name := range names {
+		populated := "no"
+		if series[name] > 0 {
+			populated = "yes"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", name, series[name], populated, collector.DataSource(name))
+	}
+	return tw.Flush()
+}
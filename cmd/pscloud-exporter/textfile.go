@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// encodeMetrics writes metric families to w in the Prometheus text format
+func encodeMetrics(w io.Writer, families []*dto.MetricFamily) error {
+	encoder := expfmt.NewEncoder(w, expfmt.NewFormat(expfmt.TypeTextPlain))
+	for _, family := range families {
+		if err := encoder.Encode(family); err != nil {
+			return fmt.Errorf("failed to encode metrics: %w", err)
+		}
+	}
+	return nil
+}
+
+// writeTextfile gathers all metrics and atomically writes them to path
+// in the Prometheus text format, as expected by the node_exporter textfile collector
+func writeTextfile(gatherer prometheus.Gatherer, path string) error {
+	families, err := gatherer.Gather()
+	if err != nil {
+		return fmt.Errorf("failed to gather metrics: %w", err)
+	}
+
+	// Write to a temporary file in the same directory and rename it into place
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := encodeMetrics(tmp, families); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+
+	// Temporary files are created with mode 0600; make the result readable by the collector
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to set file mode: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// jitteredInterval returns interval extended by a random offset of up to jitter times the interval
+func jitteredInterval(interval time.Duration, jitter float64) time.Duration {
+	if jitter <= 0 {
+		return interval
+	}
+	return interval + time.Duration(rand.Float64()*jitter*float64(interval))
+}
+
+// backoffInterval returns interval doubled once per consecutive failure, capped at maxBackoff
+func backoffInterval(interval, maxBackoff time.Duration, failures int) time.Duration {
+	delay := interval
+	for i := 0; i < failures && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
+
+// runTextfileWriter writes metrics to path immediately and then on every interval until stop is closed.
+// Each interval is extended by a random jitter so that exporters started together spread their API load.
+// While writes or scrapes keep failing, including scrapes whose endpoints are skipped by an open circuit
+// breaker, the interval doubles up to maxBackoff and returns to normal after the first success.
+// The extra delay is reported by backoffMetric.
+func runTextfileWriter(gatherer prometheus.Gatherer, path string, interval time.Duration, jitter float64, maxBackoff time.Duration, failing func() bool, backoffMetric prometheus.Gauge, stop <-chan struct{}) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	failures, delay := 0, interval
+	for {
+		select {
+		case <-timer.C:
+		case <-stop:
+			return
+		}
+
+		err := writeTextfile(gatherer, path)
+		switch {
+		case err != nil:
+			failures++
+			log.Printf("Error writing metrics to %s: %v", path, err)
+		case failing():
+			failures++
+		default:
+			if failures > 0 {
+				log.Printf("Scrape succeeded after %d failed attempts, back to writing every %s", failures, interval)
+			}
+			failures = 0
+		}
+
+		// Only log when the backoff grows so that a long outage does not flood the log
+		next := backoffInterval(interval, maxBackoff, failures)
+		if next > delay {
+			log.Printf("%d consecutive failed scrapes, backing off to %s", failures, next)
+		}
+		delay = next
+		backoffMetric.Set((delay - interval).Seconds())
+
+		timer.Reset(jitteredInterval(delay, jitter))
+	}
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// ErrCircuitOpen is returned instead of calling an endpoint whose circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// Circuit breaker defaults used when none are configured
+const (
+	DefaultCircuitFailureThreshold = 5
+	DefaultCircuitCooldown         = 5 * time.Minute
+)
+
+// circuitBreaker tracks the consecutive failures of a single endpoint
+type circuitBreaker struct {
+	failures  int       // consecutive failed requests
+	openUntil time.Time // calls are skipped until this time once the threshold is reached
+	probing   bool      // a probe request is in flight after the cooldown
+}
+
+// allowRequest returns ErrCircuitOpen if calls to endpoint are currently skipped.
+// After the cooldown a single probe request is let through; its outcome closes
+// or reopens the circuit.
+func (c *Client) allowRequest(endpoint string) error {
+	if c.circuitThreshold <= 0 {
+		return nil
+	}
+
+	name := endpointName(endpoint)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	breaker := c.circuits[name]
+	if breaker == nil || breaker.failures < c.circuitThreshold {
+		return nil
+	}
+	if breaker.probing || time.Now().Before(breaker.openUntil) {
+		return fmt.Errorf("%w: %s", ErrCircuitOpen, name)
+	}
+
+	breaker.probing = true
+	return nil
+}
+
+// recordOutcome updates the circuit breaker of endpoint with the result of a request.
+// GraphQL and authentication errors mean the endpoint answered and do not count as failures.
+func (c *Client) recordOutcome(endpoint string, err error) {
+	if c.circuitThreshold <= 0 {
+		return
+	}
+
+	name := endpointName(endpoint)
+	failed := err != nil && !errors.Is(err, ErrGraphQL) && !errors.Is(err, ErrUnauthenticated)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	breaker := c.circuits[name]
+	if breaker == nil {
+		breaker = &circuitBreaker{}
+		c.circuits[name] = breaker
+	}
+	breaker.probing = false
+
+	if !failed {
+		if breaker.failures >= c.circuitThreshold {
+			log.Printf("Endpoint %s recovered, closing its circuit breaker", name)
+		}
+		breaker.failures = 0
+		return
+	}
+
+	breaker.failures++
+	if breaker.failures >= c.circuitThreshold {
+		if breaker.failures == c.circuitThreshold {
+			log.Printf("Warning: endpoint %s failed %d times in a row, skipping it for %s", name, breaker.failures, c.circuitCooldown)
+		}
+		breaker.openUntil = time.Now().Add(c.circuitCooldown)
+	}
+}
+
+// CircuitStates returns whether the circuit breaker of each endpoint called so far is open
+func (c *Client) CircuitStates() map[string]bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	states := make(map[string]bool, len(c.circuits))
+	for name, breaker := range c.circuits {
+		states[name] = c.circuitThreshold > 0 && breaker.failures >= c.circuitThreshold
+	}
+	return states
+}
@@ -1,9 +1,18 @@
 package client
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-resty/resty/v2"
 )
@@ -19,11 +28,79 @@ const (
 	lbaasGraphQLEndpoint   = "https://console.ps.kz/lbaas/graphql"
 )
 
+// ErrUnauthenticated is returned when the API rejects the token
+var ErrUnauthenticated = errors.New("authentication required")
+
+// ErrGraphQL is returned when the API responds with a GraphQL error
+var ErrGraphQL = errors.New("GraphQL error")
+
+// ErrServiceDisabled is returned instead of calling a service disabled in the client options
+var ErrServiceDisabled = errors.New("service disabled")
+
+// Services are the names of the PS.KZ GraphQL services, as used in DisabledServices
+var Services = []string{"account", "domains", "cloud", "vps", "k8saas", "lbaas"}
+
+// ErrNonJSONResponse is returned when the API answers with something other than JSON,
+// typically an HTML maintenance or error page
+var ErrNonJSONResponse = errors.New("non-JSON response")
+
+// nonJSONSnippetLength is the number of characters of a non-JSON body included in the error
+const nonJSONSnippetLength = 200
+
+// TokenProvider returns a fresh API token, e.g. after the current one expired
+type TokenProvider func() (string, error)
+
 // Client represents the PS.KZ API client
 type Client struct {
-	client  *resty.Client
-	token   string
-	baseURL string
+	client         *resty.Client
+	token          string
+	baseURL        string
+	authHeaderMode string
+
+	tokenProvider TokenProvider
+
+	defaultPerPage int            // page size of paginated queries, 0 for their built-in ones
+	perPage        map[string]int // per-query page size overrides
+
+	mutex       sync.Mutex
+	rateLimits  map[string]RateLimit // last seen rate limit per endpoint
+	reauthCount int                  // number of re-authentications performed
+
+	responseSizes []ResponseSize // response sizes not yet taken by TakeResponseSizes
+
+	planUnsupported            bool // the account query rejected the plan field
+	readyNodesUnsupported      bool // the K8S cluster query rejected the readyNodeCount field
+	flavorDiskUnsupported      bool // the K8S cluster query rejected the flavor disk field
+	clusterHealthUnsupported   bool // the K8S cluster query rejected the healthStatus field
+	cardListUnsupported        bool // the account information query rejected the bankCards list
+	serviceTypeUnsupported     bool // the account services query rejected the type field
+	fixedIPsUnsupported        bool // the cloud instance query rejected the fixedIpsArray field
+	cloudImageUnsupported      bool // the cloud instance query rejected the imageName field
+	vpsImageUnsupported        bool // the VPS server query rejected the imageName field
+	vpsDatesUnsupported        bool // the VPS server query rejected the createdAt and paidTill fields
+	domainWhoisUnsupported     bool // the domain query rejected the whois field
+	domainDNSUnsupported       bool // the domain query rejected the nameservers and dnssec fields
+	domainAutoRenewUnsupported bool // the domain query rejected the autoRenew field
+	memberStatusUnsupported    bool // the LBaaS query rejected the member operatingStatus field
+	lbaasDatesUnsupported      bool // the LBaaS query rejected the createdAt and updatedAt fields
+
+	disabledServices map[string]bool // services that are never called
+
+	circuitThreshold int                        // consecutive failures that open a circuit, 0 or less disables
+	circuitCooldown  time.Duration              // time an open circuit skips its endpoint
+	circuits         map[string]*circuitBreaker // circuit breakers per endpoint name
+}
+
+// ResponseSize is the body size of a single API response
+type ResponseSize struct {
+	Endpoint string
+	Bytes    int
+}
+
+// RateLimit represents the rate limit state reported by the API
+type RateLimit struct {
+	Remaining float64
+	Reset     time.Time
 }
 
 // GraphQLRequest represents a GraphQL request
@@ -58,22 +135,204 @@ type BalanceResponse struct {
 	} `json:"data"`
 }
 
+// DomainItem represents a single domain in the domain list
+type DomainItem struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	ExpiryDate string `json:"expiryDate"`
+	// Nameservers, DNSSEC and AutoRenew are nil when the API does not return them
+	Nameservers []string `json:"nameservers,omitempty"`
+	DNSSEC      *bool    `json:"dnssec,omitempty"`
+	AutoRenew   *bool    `json:"autoRenew,omitempty"`
+	// Whois is nil when the API does not return whois details
+	Whois *DomainWhoisInfo `json:"whois,omitempty"`
+}
+
+// DomainWhoisInfo holds the optional whois timestamps of a domain
+type DomainWhoisInfo struct {
+	Transfer *WhoisTimestamp `json:"transfer,omitempty"`
+	Update   *WhoisTimestamp `json:"update,omitempty"`
+	// ContactWhois is nil when the API does not return contact details
+	ContactWhois *DomainContactWhois `json:"contactWhois,omitempty"`
+}
+
+// DomainContactWhois holds the whois contacts of a domain
+type DomainContactWhois struct {
+	RegistrantContact *WhoisContact `json:"registrantContact,omitempty"`
+	AdminContact      *WhoisContact `json:"adminContact,omitempty"`
+}
+
+// WhoisContact is a whois contact with its privacy protection and last update
+type WhoisContact struct {
+	Privacy *bool           `json:"privacy,omitempty"`
+	Update  *WhoisTimestamp `json:"update,omitempty"`
+}
+
+// WhoisTimestamp is a whois date with its Unix time in seconds
+type WhoisTimestamp struct {
+	Unix int64 `json:"unix"`
+}
+
 // DomainListResponse represents the structure of the response with the list of domains
 type DomainListResponse struct {
 	Data struct {
 		Domains struct {
-			Items []struct {
-				Name       string `json:"name"`
-				Status     string `json:"status"`
-				ExpiryDate string `json:"expiryDate"`
-			} `json:"items"`
+			Items []DomainItem `json:"items"`
 		} `json:"domains"`
 	} `json:"data"`
 }
 
+// Authentication header modes
+const (
+	// AuthHeaderToken sends only the X-User-Token header
+	AuthHeaderToken = "token"
+	// AuthHeaderBearer sends only the Authorization: Bearer header
+	AuthHeaderBearer = "bearer"
+	// AuthHeaderBoth sends both headers
+	AuthHeaderBoth = "both"
+)
+
 // ClientOptions contains optional settings for the API client
 type ClientOptions struct {
 	BaseURL string
+	// AuthHeaderMode selects which authentication headers are sent: token, bearer or both (default)
+	AuthHeaderMode string
+	// TokenProvider, if set, is used to obtain a new token when the API reports UNAUTHENTICATED
+	TokenProvider TokenProvider
+	// DefaultPerPage, if set, is the page size of all paginated queries instead of their built-in ones
+	DefaultPerPage int
+	// PerPage overrides the page size of individual queries, keyed by the names in PaginatedQueries
+	PerPage map[string]int
+	// CircuitFailureThreshold is the number of consecutive failures after which an endpoint
+	// is skipped for CircuitCooldown. Zero uses the default, a negative value disables the breaker.
+	CircuitFailureThreshold int
+	CircuitCooldown         time.Duration
+	// DisabledServices are services that are not called, e.g. because the token has
+	// no access to them. Queries to them fail with ErrServiceDisabled.
+	DisabledServices []string
+	// LocalAddress, if set, is the source IP requests to the API are sent from,
+	// e.g. the address allowlisted by an egress firewall on a multi-homed host
+	LocalAddress string
+	// Transport, if set, sends the HTTP requests instead of the default transport,
+	// e.g. to serve them from a test server. LocalAddress is ignored then.
+	Transport http.RoundTripper
+}
+
+// Page size limits accepted by the API, page sizes outside are clamped
+const (
+	MinPerPage = 1
+	MaxPerPage = 1000
+)
+
+// PaginatedQueries are the names of the queries whose page size can be overridden
+var PaginatedQueries = []string{
+	"cloudInstances",
+	"cloudServers",
+	"dnsRecords",
+	"invoices",
+	"k8sClusters",
+	"k8sProjects",
+	"lbaas",
+	"projects",
+	"vpsServers",
+	"vpsServersStatus",
+}
+
+// ValidatePerPage checks that the per-query page size overrides name known queries
+func ValidatePerPage(perPage map[string]int) error {
+	for name := range perPage {
+		known := false
+		for _, query := range PaginatedQueries {
+			if name == query {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown paginated query %q (expected one of %s)", name, strings.Join(PaginatedQueries, ", "))
+		}
+	}
+	return nil
+}
+
+// ValidateDisabledServices checks that the disabled services are known.
+// The account service is needed for the authentication check and cannot be disabled.
+func ValidateDisabledServices(services []string) error {
+	for _, name := range services {
+		if name == "account" {
+			return fmt.Errorf("the account service cannot be disabled")
+		}
+		known := false
+		for _, service := range Services {
+			if name == service {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown service %q (expected one of %s)", name, strings.Join(Services, ", "))
+		}
+	}
+	return nil
+}
+
+// ValidateAuthHeaderMode checks that mode is a supported authentication header mode
+func ValidateAuthHeaderMode(mode string) error {
+	switch mode {
+	case "", AuthHeaderToken, AuthHeaderBearer, AuthHeaderBoth:
+		return nil
+	}
+	return fmt.Errorf("unsupported auth header mode %q (expected %s, %s or %s)",
+		mode, AuthHeaderToken, AuthHeaderBearer, AuthHeaderBoth)
+}
+
+// ValidateLocalAddress checks that address is an IP address assigned to this host
+func ValidateLocalAddress(address string) error {
+	if address == "" {
+		return nil
+	}
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return fmt.Errorf("invalid local address %q: not an IP address", address)
+	}
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return fmt.Errorf("listing interface addresses: %w", err)
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.Equal(ip) {
+			return nil
+		}
+	}
+	return fmt.Errorf("local address %s is not assigned to any network interface", address)
+}
+
+// localAddressTransport returns a transport with the default settings whose
+// connections are made from the local address ip
+func localAddressTransport(ip net.IP) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		LocalAddr: &net.TCPAddr{IP: ip},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	return transport
+}
+
+// rejectsField reports whether err is a GraphQL error that names one of fields,
+// meaning the API does not know the optional field. Other GraphQL errors, such as
+// permission, rate limit or internal errors, must not disable optional fields.
+func rejectsField(err error, fields ...string) bool {
+	if !errors.Is(err, ErrGraphQL) {
+		return false
+	}
+	for _, field := range fields {
+		if strings.Contains(err.Error(), field) {
+			return true
+		}
+	}
+	return false
 }
 
 // New creates a new PS.KZ API client with default settings
@@ -89,17 +348,252 @@ func NewWithOptions(token string, options ClientOptions) *Client {
 		baseURL = options.BaseURL
 	}
 
+	authHeaderMode := AuthHeaderBoth
+	if options.AuthHeaderMode != "" {
+		authHeaderMode = options.AuthHeaderMode
+	}
+
+	circuitThreshold := DefaultCircuitFailureThreshold
+	if options.CircuitFailureThreshold != 0 {
+		circuitThreshold = options.CircuitFailureThreshold
+	}
+
+	circuitCooldown := DefaultCircuitCooldown
+	if options.CircuitCooldown > 0 {
+		circuitCooldown = options.CircuitCooldown
+	}
+
+	disabledServices := make(map[string]bool, len(options.DisabledServices))
+	for _, name := range options.DisabledServices {
+		disabledServices[name] = true
+	}
+
 	client := resty.New()
+	if options.Transport != nil {
+		client.SetTransport(options.Transport)
+	} else if ip := net.ParseIP(options.LocalAddress); ip != nil {
+		client.SetTransport(localAddressTransport(ip))
+	}
 
 	return &Client{
-		client:  client,
-		token:   token,
-		baseURL: baseURL,
+		client:         client,
+		token:          token,
+		baseURL:        baseURL,
+		authHeaderMode: authHeaderMode,
+		tokenProvider:  options.TokenProvider,
+		defaultPerPage: options.DefaultPerPage,
+		perPage:        options.PerPage,
+		rateLimits:     make(map[string]RateLimit),
+
+		disabledServices: disabledServices,
+
+		circuitThreshold: circuitThreshold,
+		circuitCooldown:  circuitCooldown,
+		circuits:         make(map[string]*circuitBreaker),
+	}
+}
+
+// pageSize returns the page size of the named query: its override if set,
+// otherwise the configured default, otherwise fallback. The result is clamped
+// to the range accepted by the API.
+func (c *Client) pageSize(query string, fallback int) int {
+	size := fallback
+	if c.defaultPerPage != 0 {
+		size = c.defaultPerPage
+	}
+	if override, ok := c.perPage[query]; ok && override != 0 {
+		size = override
+	}
+
+	switch {
+	case size < MinPerPage:
+		return MinPerPage
+	case size > MaxPerPage:
+		return MaxPerPage
+	}
+	return size
+}
+
+// ServiceEnabled reports whether the named service is called by the client
+func (c *Client) ServiceEnabled(name string) bool {
+	return !c.disabledServices[name]
+}
+
+// BaseURL returns the base URL the client sends requests to
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// Close closes the idle keep-alive connections of the client. Connections in
+// use are left to finish. It is safe to call with any transport and more than once.
+func (c *Client) Close() {
+	c.client.GetClient().CloseIdleConnections()
+}
+
+// RateLimits returns the last rate limit state reported per endpoint.
+// Endpoints that never returned rate limit headers are not included.
+func (c *Client) RateLimits() map[string]RateLimit {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	limits := make(map[string]RateLimit, len(c.rateLimits))
+	for endpoint, limit := range c.rateLimits {
+		limits[endpoint] = limit
+	}
+	return limits
+}
+
+// endpointName returns a short service name for an endpoint, e.g. "account" for .../account/graphql
+func endpointName(endpoint string) string {
+	name := strings.TrimSuffix(strings.TrimSuffix(endpoint, "/"), "/graphql")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// recordRateLimit stores the rate limit headers of a response if present
+func (c *Client) recordRateLimit(endpoint string, header http.Header) {
+	remaining := header.Get("X-RateLimit-Remaining")
+	if remaining == "" {
+		return
+	}
+
+	value, err := strconv.ParseFloat(remaining, 64)
+	if err != nil {
+		return
 	}
+	limit := RateLimit{Remaining: value}
+
+	// The reset header is either a Unix timestamp or a number of seconds until reset
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		if reset > 1000000000 {
+			limit.Reset = time.Unix(reset, 0)
+		} else {
+			limit.Reset = time.Now().Add(time.Duration(reset) * time.Second)
+		}
+	}
+
+	c.mutex.Lock()
+	c.rateLimits[endpointName(endpoint)] = limit
+	c.mutex.Unlock()
 }
 
-// executeQuery executes a GraphQL query
+// ReauthCount returns the number of re-authentications performed after UNAUTHENTICATED errors
+func (c *Client) ReauthCount() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.reauthCount
+}
+
+// TakeResponseSizes returns the response sizes recorded since the previous call
+func (c *Client) TakeResponseSizes() []ResponseSize {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	sizes := c.responseSizes
+	c.responseSizes = nil
+	return sizes
+}
+
+// recordResponseSize stores the body size of a response
+func (c *Client) recordResponseSize(endpoint string, size int) {
+	c.mutex.Lock()
+	c.responseSizes = append(c.responseSizes, ResponseSize{Endpoint: endpointName(endpoint), Bytes: size})
+	c.mutex.Unlock()
+}
+
+// currentToken returns the token used for requests
+func (c *Client) currentToken() string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.token
+}
+
+// reauthenticate obtains a new token from the token provider
+func (c *Client) reauthenticate() error {
+	token, err := c.tokenProvider()
+	if err != nil {
+		return fmt.Errorf("failed to obtain new token: %w", err)
+	}
+
+	c.mutex.Lock()
+	c.token = token
+	c.reauthCount++
+	c.mutex.Unlock()
+	return nil
+}
+
+// executeQuery executes a GraphQL query.
+// If the API reports UNAUTHENTICATED and a token provider is configured,
+// the token is refreshed and the query is retried exactly once.
+// Endpoints whose circuit breaker is open are not called and ErrCircuitOpen is returned,
+// disabled services are not called and ErrServiceDisabled is returned.
 func (c *Client) executeQuery(endpoint, query string, variables map[string]interface{}, result interface{}) error {
+	if name := endpointName(endpoint); c.disabledServices[name] {
+		return fmt.Errorf("%w: %s", ErrServiceDisabled, name)
+	}
+	if err := c.allowRequest(endpoint); err != nil {
+		return err
+	}
+
+	err := c.authenticatedQuery(endpoint, query, variables, result)
+	c.recordOutcome(endpoint, err)
+	return err
+}
+
+// authenticatedQuery performs a GraphQL request, re-authenticating once on UNAUTHENTICATED
+func (c *Client) authenticatedQuery(endpoint, query string, variables map[string]interface{}, result interface{}) error {
+	err := c.doQuery(endpoint, query, variables, result)
+	if err == nil || c.tokenProvider == nil || !errors.Is(err, ErrUnauthenticated) {
+		return err
+	}
+
+	if reauthErr := c.reauthenticate(); reauthErr != nil {
+		return fmt.Errorf("%w (re-authentication failed: %v)", err, reauthErr)
+	}
+
+	return c.doQuery(endpoint, query, variables, result)
+}
+
+// isNonJSON reports whether a response is not JSON, judging by its content type
+// if it names a non-JSON type, and otherwise by the body starting with "<".
+// text/plain is not trusted either way, as proxies use it for JSON too.
+func isNonJSON(contentType string, body []byte) bool {
+	if contentType != "" && !strings.Contains(strings.ToLower(contentType), "json") &&
+		!strings.HasPrefix(strings.ToLower(contentType), "text/plain") {
+		return true
+	}
+	return bytes.HasPrefix(bytes.TrimSpace(body), []byte("<"))
+}
+
+// bodySnippet returns the start of a response body with whitespace collapsed
+func bodySnippet(body []byte) string {
+	snippet := []rune(strings.Join(strings.Fields(string(body)), " "))
+	if len(snippet) > nonJSONSnippetLength {
+		return string(snippet[:nonJSONSnippetLength]) + "..."
+	}
+	return string(snippet)
+}
+
+// ExecuteQuery runs a GraphQL query with the client's authentication, re-authentication
+// and error handling and returns the raw "data" field of the response. endpoint is
+// either an absolute URL or a path relative to the base URL. It is meant for custom
+// tooling that needs fields the exporter does not query; results are not turned into metrics.
+func (c *Client) ExecuteQuery(endpoint, query string, variables map[string]interface{}) (json.RawMessage, error) {
+	if endpoint == "" {
+		return nil, errors.New("endpoint must not be empty")
+	}
+
+	var response GraphQLResponse
+	if err := c.executeQuery(endpoint, query, variables, &response); err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+// doQuery performs a single GraphQL request
+func (c *Client) doQuery(endpoint, query string, variables map[string]interface{}, result interface{}) error {
 	reqBody := GraphQLRequest{
 		Query:     query,
 		Variables: variables,
@@ -116,23 +610,41 @@ func (c *Client) executeQuery(endpoint, query string, variables map[string]inter
 		finalEndpoint = c.baseURL + endpoint
 	}
 
-	// Create request using resty client
-	resp, err := c.client.R().
+	// Create request using resty client. JSON is requested explicitly
+	// so that a change of the server's default format cannot break decoding.
+	req := c.client.R().
 		SetHeader("Content-Type", "application/json").
-		SetHeader("X-User-Token", c.token).
-		SetHeader("Authorization", "Bearer "+c.token).
-		SetBody(jsonBody).
-		Post(finalEndpoint)
+		SetHeader("Accept", "application/json").
+		SetBody(jsonBody)
+
+	// Set authentication headers according to the configured mode
+	token := c.currentToken()
+	if c.authHeaderMode != AuthHeaderBearer {
+		req.SetHeader("X-User-Token", token)
+	}
+	if c.authHeaderMode != AuthHeaderToken {
+		req.SetHeader("Authorization", "Bearer "+token)
+	}
+
+	resp, err := req.Post(finalEndpoint)
 
 	if err != nil {
 		return fmt.Errorf("failed to execute request: %w", err)
 	}
 
+	c.recordRateLimit(finalEndpoint, resp.Header())
+	c.recordResponseSize(finalEndpoint, len(resp.Body()))
+
 	// Check response status
 	if resp.StatusCode() != http.StatusOK {
 		return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode(), string(resp.Body()))
 	}
 
+	// Maintenance and error pages are served as HTML, possibly with status 200
+	if isNonJSON(resp.Header().Get("Content-Type"), resp.Body()) {
+		return fmt.Errorf("%w (content type %q): %s", ErrNonJSONResponse, resp.Header().Get("Content-Type"), bodySnippet(resp.Body()))
+	}
+
 	// Parse GraphQL response
 	var graphQLResp GraphQLResponse
 	if err := json.Unmarshal(resp.Body(), &graphQLResp); err != nil {
@@ -144,20 +656,65 @@ func (c *Client) executeQuery(endpoint, query string, variables map[string]inter
 		if graphQLResp.Errors[0].Extensions.Code == "UNAUTHENTICATED" {
 			authURL := graphQLResp.Errors[0].Extensions.AuthURL
 			if authURL != "" {
-				return fmt.Errorf("authentication required: please authenticate at %s", authURL)
+				return fmt.Errorf("%w: please authenticate at %s", ErrUnauthenticated, authURL)
 			}
+			return fmt.Errorf("%w: GraphQL error: %s", ErrUnauthenticated, graphQLResp.Errors[0].Message)
 		}
-		return fmt.Errorf("GraphQL error: %s", graphQLResp.Errors[0].Message)
+		return fmt.Errorf("%w: %s", ErrGraphQL, graphQLResp.Errors[0].Message)
 	}
 
-	// Decode the received data into the required structure
-	if err := json.Unmarshal(graphQLResp.Data, result); err != nil {
+	// Decode the full response, including the "data" envelope, into the required structure
+	if err := json.Unmarshal(resp.Body(), result); err != nil {
 		return fmt.Errorf("failed to unmarshal response data: %w", err)
 	}
 
 	return nil
 }
 
+// EndpointStatus is the result of a connectivity check of a GraphQL service
+type EndpointStatus struct {
+	Name     string
+	Endpoint string
+	Duration time.Duration
+	Err      error
+	// Disabled is set for services that are disabled and therefore not checked
+	Disabled bool
+}
+
+// VerifyEndpoints sends a minimal query to every enabled GraphQL service and
+// reports whether each of them is reachable and accepts the token. Disabled
+// services are reported as such without being queried.
+func (c *Client) VerifyEndpoints() []EndpointStatus {
+	endpoints := []string{
+		accountGraphQLEndpoint,
+		domainsGraphQLEndpoint,
+		cloudGraphQLEndpoint,
+		vpsGraphQLEndpoint,
+		k8saasGraphQLEndpoint,
+		lbaasGraphQLEndpoint,
+	}
+
+	statuses := make([]EndpointStatus, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		if !c.ServiceEnabled(endpointName(endpoint)) {
+			statuses = append(statuses, EndpointStatus{Name: endpointName(endpoint), Endpoint: endpoint, Disabled: true})
+			continue
+		}
+
+		var response map[string]interface{}
+		start := time.Now()
+		err := c.executeQuery(endpoint, `query { __typename }`, nil, &response)
+		statuses = append(statuses, EndpointStatus{
+			Name:     endpointName(endpoint),
+			Endpoint: endpoint,
+			Duration: time.Since(start),
+			Err:      err,
+		})
+	}
+
+	return statuses
+}
+
 // GetBalance returns account balance information
 func (c *Client) GetBalance() (*BalanceResponse, error) {
 	query := `
@@ -240,53 +797,393 @@ func (c *Client) GetBalance() (*BalanceResponse, error) {
 	return result, nil
 }
 
-// GetDomains returns a list of domains
-func (c *Client) GetDomains() (*DomainListResponse, error) {
-	// Verify authentication
-	_, err := c.GetAccountBalance()
+// AccountVerification represents the verification state of the account
+type AccountVerification struct {
+	IsVerified bool   `json:"isVerified"`
+	ExpiresAt  string `json:"verificationExpiresAt"`
+}
+
+// BankCard is a payment card attached to the account
+type BankCard struct {
+	ID   string `json:"_id"`
+	Mask string `json:"mask"`
+	// ExpiresAt is a date string or a Unix timestamp, nil when the API does not report it
+	ExpiresAt interface{} `json:"expiresAt"`
+}
+
+// BankCards holds the number of payment cards attached to the account
+type BankCards struct {
+	Count int
+	// Cards is nil when the API does not list the cards
+	Cards []BankCard
+}
+
+// GetBankCards returns the number of payment cards attached to the account and,
+// when available, the cards with their expiry dates. The card list is dropped
+// from the query after the first GraphQL error and only the count is fetched.
+func (c *Client) GetBankCards() (*BankCards, error) {
+	query := `
+	query {
+		k8saas {
+			account {
+				getAccountInformation {
+					accountInfo {
+						counters {
+							bankCards
+						}
+						%s
+					}
+				}
+			}
+		}
+	}
+	`
+
+	cardList := `bankCards {
+							_id
+							mask
+							expiresAt
+						}`
+
+	var response struct {
+		Data struct {
+			K8saas struct {
+				Account struct {
+					GetAccountInformation struct {
+						AccountInfo struct {
+							Counters struct {
+								BankCards int `json:"bankCards"`
+							} `json:"counters"`
+							BankCards []BankCard `json:"bankCards"`
+						} `json:"accountInfo"`
+					} `json:"getAccountInformation"`
+				} `json:"account"`
+			} `json:"k8saas"`
+		} `json:"data"`
+	}
+
+	c.mutex.Lock()
+	withCards := !c.cardListUnsupported
+	c.mutex.Unlock()
+
+	var err error
+	if withCards {
+		err = c.executeQuery(k8saasGraphQLEndpoint, fmt.Sprintf(query, cardList), nil, &response)
+		if rejectsField(err, "bankCards", "mask", "expiresAt") {
+			// The API does not list the cards, stop asking for them
+			log.Printf("Warning: bank card list is not available, querying only the card count: %v", err)
+			c.mutex.Lock()
+			c.cardListUnsupported = true
+			c.mutex.Unlock()
+			withCards = false
+		}
+	}
+	if !withCards {
+		err = c.executeQuery(k8saasGraphQLEndpoint, fmt.Sprintf(query, ""), nil, &response)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bank cards: %w", err)
+	}
+
+	info := response.Data.K8saas.Account.GetAccountInformation.AccountInfo
+	cards := &BankCards{Count: info.Counters.BankCards}
+	if withCards {
+		cards.Cards = info.BankCards
+		if cards.Cards == nil {
+			cards.Cards = []BankCard{}
+		}
+	}
+	return cards, nil
+}
+
+// GetAccountVerification returns the verification state of the account.
+// ExpiresAt is empty for accounts without verification data.
+func (c *Client) GetAccountVerification() (*AccountVerification, error) {
+	query := `
+	query {
+		account {
+			current {
+				info {
+					isVerified
+					verificationExpiresAt
+				}
+			}
+		}
+	}
+	`
+
+	var response struct {
+		Data struct {
+			Account struct {
+				Current struct {
+					Info AccountVerification `json:"info"`
+				} `json:"current"`
+			} `json:"account"`
+		} `json:"data"`
+	}
+
+	err := c.executeQuery(accountGraphQLEndpoint, query, nil, &response)
 	if err != nil {
-		return nil, fmt.Errorf("failed to authenticate before getting domains: %w", err)
+		return nil, fmt.Errorf("failed to get account verification: %w", err)
 	}
 
-	// Create empty domain list for compatibility
-	result := &DomainListResponse{
-		Data: struct {
-			Domains struct {
-				Items []struct {
-					Name       string `json:"name"`
-					Status     string `json:"status"`
-					ExpiryDate string `json:"expiryDate"`
-				} `json:"items"`
-			} `json:"domains"`
-		}{
-			Domains: struct {
-				Items []struct {
-					Name       string `json:"name"`
-					Status     string `json:"status"`
-					ExpiryDate string `json:"expiryDate"`
-				} `json:"items"`
-			}{
-				Items: []struct {
-					Name       string `json:"name"`
-					Status     string `json:"status"`
-					ExpiryDate string `json:"expiryDate"`
-				}{},
-			},
-		},
+	return &response.Data.Account.Current.Info, nil
+}
+
+// domainWhoisField selects the whois timestamps and contacts of a domain
+const domainWhoisField = `
+				whois {
+					update { unix }
+					transfer { unix }
+					contactWhois {
+						registrantContact {
+							privacy
+							update { unix }
+						}
+						adminContact {
+							privacy
+							update { unix }
+						}
+					}
+				}`
+
+// domainWhoisFields are the field names of domainWhoisField a GraphQL error may name
+var domainWhoisFields = []string{"whois", "contactWhois", "registrantContact", "adminContact", "privacy"}
+
+// domainDNSField selects the delegation details of a domain
+const domainDNSField = `
+				nameservers
+				dnssec`
+
+// GetDomains returns a list of domains, filtered by status unless statuses is empty.
+// The auto-renew status is only selected if autoRenew is set.
+func (c *Client) GetDomains(statuses []string, autoRenew bool) (*DomainListResponse, error) {
+	query := `
+	query($statuses: [String]) {
+		domains {
+			items(filter: { status: $statuses }) {
+				name
+				status
+				expiryDate%s
+			}
+		}
 	}
+	`
 
-	// Skip domain request if API doesn't support it
-	// Authentication was successful, so return empty domain list
-	return result, nil
+	// An empty filter means all domains
+	variables := map[string]interface{}{
+		"statuses": nil,
+	}
+	if len(statuses) > 0 {
+		variables["statuses"] = statuses
+	}
+
+	c.mutex.Lock()
+	withWhois := !c.domainWhoisUnsupported
+	withDNS := !c.domainDNSUnsupported
+	withAutoRenew := autoRenew && !c.domainAutoRenewUnsupported
+	c.mutex.Unlock()
+
+	// Optional fields the API rejects are dropped and the query is retried
+	var response DomainListResponse
+	var err error
+	for {
+		fields := ""
+		if withDNS {
+			fields += domainDNSField
+		}
+		if withAutoRenew {
+			fields += "\n\t\t\t\tautoRenew"
+		}
+		if withWhois {
+			fields += domainWhoisField
+		}
+
+		response = DomainListResponse{}
+		err = c.executeQuery(domainsGraphQLEndpoint, fmt.Sprintf(query, fields), variables, &response)
+		whoisRejected := withWhois && rejectsField(err, domainWhoisFields...)
+		dnsRejected := withDNS && rejectsField(err, "nameservers", "dnssec")
+		autoRenewRejected := withAutoRenew && rejectsField(err, "autoRenew")
+		if !whoisRejected && !dnsRejected && !autoRenewRejected {
+			break
+		}
+
+		c.mutex.Lock()
+		if whoisRejected {
+			// The API does not report whois details, stop asking for them
+			log.Printf("Warning: Domain whois details are not available, querying domains without them: %v", err)
+			c.domainWhoisUnsupported = true
+			withWhois = false
+		}
+		if dnsRejected {
+			// The API does not report delegation details, stop asking for them
+			log.Printf("Warning: Domain nameservers and DNSSEC are not available, querying domains without them: %v", err)
+			c.domainDNSUnsupported = true
+			withDNS = false
+		}
+		if autoRenewRejected {
+			// The API does not report the auto-renew status, stop asking for it
+			log.Printf("Warning: Domain auto-renew status is not available, querying domains without it: %v", err)
+			c.domainAutoRenewUnsupported = true
+			withAutoRenew = false
+		}
+		c.mutex.Unlock()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get domains: %w", err)
+	}
+
+	if response.Data.Domains.Items == nil {
+		response.Data.Domains.Items = []DomainItem{}
+	}
+	return &response, nil
+}
+
+// ZonePrice represents the prices of a domain zone
+type ZonePrice struct {
+	Register float64 `json:"register"`
+	Renew    float64 `json:"renew"`
+	Transfer float64 `json:"transfer"`
+}
+
+// UnmarshalJSON accepts prices as numbers or numeric strings. Prices that are
+// missing or cannot be parsed are set to NaN so they can be told apart from zero.
+func (p *ZonePrice) UnmarshalJSON(data []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	p.Register = parsePrice(raw["register"])
+	p.Renew = parsePrice(raw["renew"])
+	p.Transfer = parsePrice(raw["transfer"])
+	return nil
+}
+
+// parsePrice converts a price returned by the API to a number, NaN if it is not one
+func parsePrice(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case string:
+		if price, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+			return price
+		}
+	}
+	return math.NaN()
+}
+
+// PricesResponse represents domain prices keyed by zone, e.g. "zone_kz" or "zone_com_kz".
+// Zones are decoded dynamically so that newly offered zones are picked up automatically.
+type PricesResponse struct {
+	Data struct {
+		Domains struct {
+			Prices map[string]ZonePrice `json:"prices"`
+		} `json:"domains"`
+	} `json:"data"`
+}
+
+// GetPrices returns domain registration, renewal and transfer prices for all zones
+func (c *Client) GetPrices() (*PricesResponse, error) {
+	query := `
+	query {
+		domains {
+			prices
+		}
+	}
+	`
+
+	var response PricesResponse
+	err := c.executeQuery(domainsGraphQLEndpoint, query, nil, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get domain prices: %w", err)
+	}
+
+	return &response, nil
+}
+
+// DNSRecord represents a single resource record in a DNS zone
+type DNSRecord struct {
+	Type  string  `json:"type"`
+	Name  string  `json:"name"`
+	Value string  `json:"value"`
+	TTL   float64 `json:"ttl"`
+}
+
+// dnsRecordsPerPage is the default page size used when paginating DNS zone records
+const dnsRecordsPerPage = 100
+
+// GetDNSRecords returns all DNS records of the given zone, following pagination
+func (c *Client) GetDNSRecords(domain string) ([]DNSRecord, error) {
+	query := `
+	query($domain: String!, $page: Int!, $perPage: Int!) {
+		domains {
+			dns {
+				records(domain: $domain, page: $page, perPage: $perPage) {
+					items {
+						type
+						name
+						value
+						ttl
+					}
+					count
+				}
+			}
+		}
+	}
+	`
+
+	perPage := c.pageSize("dnsRecords", dnsRecordsPerPage)
+
+	var records []DNSRecord
+	for page := 1; ; page++ {
+		var response struct {
+			Data struct {
+				Domains struct {
+					DNS struct {
+						Records struct {
+							Items []DNSRecord `json:"items"`
+							Count int         `json:"count"`
+						} `json:"records"`
+					} `json:"dns"`
+				} `json:"domains"`
+			} `json:"data"`
+		}
+
+		variables := map[string]interface{}{
+			"domain":  domain,
+			"page":    page,
+			"perPage": perPage,
+		}
+
+		err := c.executeQuery(domainsGraphQLEndpoint, query, variables, &response)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get DNS records for %s: %w", domain, err)
+		}
+
+		items := response.Data.Domains.DNS.Records.Items
+		records = append(records, items...)
+
+		// Stop on a short page or once the reported total has been reached
+		if len(items) < perPage || len(records) >= response.Data.Domains.DNS.Records.Count {
+			break
+		}
+	}
+
+	return records, nil
 }
 
 // GetCloudServers returns information about VPC servers
 func (c *Client) GetCloudServers(serviceId string) (map[string]interface{}, error) {
+	variables := map[string]interface{}{
+		"perPage": c.pageSize("cloudServers", 1000),
+	}
+
 	query := `
-	query {
+	query($perPage: Int) {
 		vpc {
 			instance {
-				pagination(perPage: 1000, filter: { serviceId: "` + serviceId + `", status: ACTIVE }) {
+				pagination(perPage: $perPage, filter: { serviceId: "` + serviceId + `", status: ACTIVE }) {
 					items {
 						instanceName
 						floatingIpsArray
@@ -301,7 +1198,7 @@ func (c *Client) GetCloudServers(serviceId string) (map[string]interface{}, erro
 	`
 
 	var response map[string]interface{}
-	err := c.executeQuery(cloudGraphQLEndpoint, query, nil, &response)
+	err := c.executeQuery(cloudGraphQLEndpoint, query, variables, &response)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get cloud servers: %w", err)
 	}
@@ -311,11 +1208,15 @@ func (c *Client) GetCloudServers(serviceId string) (map[string]interface{}, erro
 
 // GetVPSServers returns information about VPS servers
 func (c *Client) GetVPSServers(serviceId string) (map[string]interface{}, error) {
+	variables := map[string]interface{}{
+		"perPage": c.pageSize("vpsServers", 1000),
+	}
+
 	query := `
-	query {
+	query($perPage: Int) {
 		vpc {
 			instance {
-				pagination(perPage: 1000, filter: { serviceId: "` + serviceId + `", status: ACTIVE }) {
+				pagination(perPage: $perPage, filter: { serviceId: "` + serviceId + `", status: ACTIVE }) {
 					items {
 						instanceName
 						floatingIpsArray
@@ -330,7 +1231,7 @@ func (c *Client) GetVPSServers(serviceId string) (map[string]interface{}, error)
 	`
 
 	var response map[string]interface{}
-	err := c.executeQuery(vpsGraphQLEndpoint, query, nil, &response)
+	err := c.executeQuery(vpsGraphQLEndpoint, query, variables, &response)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get VPS servers: %w", err)
 	}
@@ -348,6 +1249,7 @@ func (c *Client) GetAccountBalance() (map[string]interface{}, error) {
 					balance
 					bonuses
 					blocked
+					%s
 					credit {
 						availableCredit
 						credit
@@ -360,8 +1262,28 @@ func (c *Client) GetAccountBalance() (map[string]interface{}, error) {
 	}
 	`
 
+	c.mutex.Lock()
+	withPlan := !c.planUnsupported
+	c.mutex.Unlock()
+
 	var response map[string]interface{}
-	err := c.executeQuery(accountGraphQLEndpoint, query, nil, &response)
+	if withPlan {
+		err := c.executeQuery(accountGraphQLEndpoint, fmt.Sprintf(query, "plan"), nil, &response)
+		if err == nil {
+			return response, nil
+		}
+		if !rejectsField(err, `"plan"`) {
+			return nil, fmt.Errorf("failed to get account balance: %w", err)
+		}
+
+		// Older API versions do not know the plan field, stop asking for it
+		log.Printf("Warning: account plan is not available, querying balance without it: %v", err)
+		c.mutex.Lock()
+		c.planUnsupported = true
+		c.mutex.Unlock()
+	}
+
+	err := c.executeQuery(accountGraphQLEndpoint, fmt.Sprintf(query, ""), nil, &response)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get account balance: %w", err)
 	}
@@ -392,21 +1314,62 @@ func (c *Client) GetDomainCounters() (map[string]interface{}, error) {
 
 // GetProjects returns a list of projects
 func (c *Client) GetProjects(statuses []string, perPage int) (map[string]interface{}, error) {
-	// Create a stub for projects for compatibility
-	response := map[string]interface{}{
-		"data": map[string]interface{}{
-			"account": map[string]interface{}{
-				"services": map[string]interface{}{
-					"pagination": map[string]interface{}{
-						"items": []interface{}{},
-						"count": float64(0),
-					},
-				},
-			},
-		},
+	query := `
+	query($statuses: [String], $perPage: Int) {
+		account {
+			services {
+				pagination(perPage: $perPage, filter: { status: $statuses }) {
+					items {
+						id
+						name
+						domain
+						status
+						%s
+						price
+						diskUsage
+						diskLimit
+						bandwidthUsage
+						bandwidthLimit
+					}
+					count
+				}
+			}
+		}
+	}
+	`
+
+	variables := map[string]interface{}{
+		"statuses": statuses,
+		"perPage":  c.pageSize("projects", perPage),
+	}
+
+	c.mutex.Lock()
+	withType := !c.serviceTypeUnsupported
+	c.mutex.Unlock()
+
+	var result map[string]interface{}
+	var err error
+	if withType {
+		err = c.executeQuery(accountGraphQLEndpoint, fmt.Sprintf(query, "type"), variables, &result)
+		if rejectsField(err, `"type"`) {
+			// The API does not report service types, stop asking for them
+			log.Printf("Warning: Service types are not available, querying projects without them: %v", err)
+			c.mutex.Lock()
+			c.serviceTypeUnsupported = true
+			c.mutex.Unlock()
+			withType = false
+		}
+	}
+	if !withType {
+		result = nil
+		err = c.executeQuery(accountGraphQLEndpoint, fmt.Sprintf(query, ""), variables, &result)
 	}
 
-	return response, nil
+	if err != nil {
+		return nil, fmt.Errorf("failed to get projects: %w", err)
+	}
+
+	return result, nil
 }
 
 // GetInvoices returns information about invoices
@@ -414,6 +1377,7 @@ func (c *Client) GetInvoices(status string, perPage int) (map[string]interface{}
 	if perPage <= 0 {
 		perPage = 20
 	}
+	perPage = c.pageSize("invoices", perPage)
 
 	query := fmt.Sprintf(`
 	query {
@@ -452,49 +1416,214 @@ func (c *Client) GetInvoices(status string, perPage int) (map[string]interface{}
 
 // GetCloudResources returns information about cloud resources
 func (c *Client) GetCloudResources() (map[string]interface{}, error) {
-	// Create a stub for Cloud resources for compatibility
-	response := map[string]interface{}{
-		"data": map[string]interface{}{
-			"vpc": map[string]interface{}{
-				"service": map[string]interface{}{
-					"quotas": map[string]interface{}{
-						"resources": []interface{}{},
-					},
-					"summary": map[string]interface{}{
-						"cpuCores":            float64(0),
-						"ramSizeGb":           float64(0),
-						"instancesCount":      float64(0),
-						"volumesCount":        float64(0),
-						"volumesSizeGb":       float64(0),
-						"networksCount":       float64(0),
-						"floatingIpsCount":    float64(0),
-						"securityGroupsCount": float64(0),
-						"routersCount":        float64(0),
-					},
-				},
-			},
-		},
+	query := `
+	query {
+		vpc {
+			service {
+				quotas {
+					resources {
+						name
+						used
+						limit
+					}
+				}
+				summary {
+					cpuCores
+					ramSizeGb
+					instancesCount
+					volumesCount
+					volumesSizeGb
+					networksCount
+					floatingIpsCount
+					securityGroupsCount
+					routersCount
+				}
+			}
+		}
 	}
+	`
 
-	return response, nil
+	var result map[string]interface{}
+	if err := c.executeQuery(cloudGraphQLEndpoint, query, nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to get cloud resources: %w", err)
+	}
+
+	return result, nil
 }
 
-// GetCloudInstances returns detailed information about cloud instances
-func (c *Client) GetCloudInstances() (map[string]interface{}, error) {
-	// Create a stub for Cloud instances for compatibility
-	response := map[string]interface{}{
-		"data": map[string]interface{}{
-			"vpc": map[string]interface{}{
-				"instance": map[string]interface{}{
-					"pagination": map[string]interface{}{
-						"items": []interface{}{},
-					},
-				},
-			},
-		},
+// CloudInstanceFields lists the additional scalar fields that may be selected in the cloud instance query
+var CloudInstanceFields = []string{
+	"availabilityZone",
+	"description",
+	"imageName",
+	"keyName",
+	"projectName",
+	"regionId",
+	"updatedAt",
+}
+
+// ValidateCloudInstanceFields checks that every field is in CloudInstanceFields.
+// Only allowlisted names are ever interpolated into the query.
+func ValidateCloudInstanceFields(fields []string) error {
+	for _, field := range fields {
+		allowed := false
+		for _, name := range CloudInstanceFields {
+			if field == name {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("unsupported cloud instance field %q (expected one of %s)",
+				field, strings.Join(CloudInstanceFields, ", "))
+		}
 	}
+	return nil
+}
 
-	return response, nil
+// cloudInstanceSelection returns the fields selected for each cloud instance,
+// including extraFields which must have passed ValidateCloudInstanceFields.
+// The image name is selected once if withImage is set, even if it is an extra field.
+func cloudInstanceSelection(extraFields []string, withImage, withFixedIPs bool) string {
+	fields := []string{
+		"instanceName",
+		"status",
+		"createdAt",
+		"flavorName",
+		"volumesAttached {\n\t\t\t\t\t\t\tvolumeSize\n\t\t\t\t\t\t}",
+		"floatingIpsArray",
+	}
+	if withImage {
+		fields = append(fields, "imageName")
+	}
+	if withFixedIPs {
+		fields = append(fields, "fixedIpsArray")
+	}
+	for _, field := range extraFields {
+		if field != "imageName" {
+			fields = append(fields, field)
+		}
+	}
+	return strings.Join(fields, "\n\t\t\t\t\t\t")
+}
+
+// executeCloudInstanceQuery runs the cloud instance query that query builds from a
+// field selection. The image name and fixed IP addresses are selected until the
+// API rejects the fields.
+func (c *Client) executeCloudInstanceQuery(query func(selection string) string, extraFields []string, variables map[string]interface{}, result interface{}) error {
+	c.mutex.Lock()
+	withImage := !c.cloudImageUnsupported
+	withFixedIPs := !c.fixedIPsUnsupported
+	c.mutex.Unlock()
+
+	// Optional fields the API rejects are dropped and the query is retried
+	for {
+		err := c.executeQuery(cloudGraphQLEndpoint, query(cloudInstanceSelection(extraFields, withImage, withFixedIPs)), variables, result)
+		imageRejected := withImage && rejectsField(err, "imageName")
+		fixedIPsRejected := withFixedIPs && rejectsField(err, "fixedIpsArray")
+		if !imageRejected && !fixedIPsRejected {
+			return err
+		}
+
+		c.mutex.Lock()
+		if imageRejected {
+			// The API does not report image names, stop asking for them
+			log.Printf("Warning: Cloud instance image names are not available, querying instances without them: %v", err)
+			c.cloudImageUnsupported = true
+			withImage = false
+		}
+		if fixedIPsRejected {
+			// The API does not report fixed IP addresses, stop asking for them
+			log.Printf("Warning: Cloud instance fixed IPs are not available, querying instances without them: %v", err)
+			c.fixedIPsUnsupported = true
+			withFixedIPs = false
+		}
+		c.mutex.Unlock()
+	}
+}
+
+// GetCloudInstances returns detailed information about cloud instances.
+// extraFields are selected in addition to the default fields and must pass ValidateCloudInstanceFields.
+func (c *Client) GetCloudInstances(extraFields []string) (map[string]interface{}, error) {
+	if err := ValidateCloudInstanceFields(extraFields); err != nil {
+		return nil, err
+	}
+
+	variables := map[string]interface{}{
+		"perPage": c.pageSize("cloudInstances", 100),
+	}
+
+	query := func(selection string) string {
+		return fmt.Sprintf(`
+	query($perPage: Int) {
+		vpc {
+			instance {
+				pagination(perPage: $perPage) {
+					items {
+						%s
+					}
+				}
+			}
+		}
+	}
+	`, selection)
+	}
+
+	var result map[string]interface{}
+	err := c.executeCloudInstanceQuery(query, extraFields, variables, &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cloud instances: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetCloudInstance returns a single cloud instance, with the same fields as the
+// items of GetCloudInstances. Any failure is returned so it can be reported for the instance.
+func (c *Client) GetCloudInstance(instanceId string, extraFields []string) (map[string]interface{}, error) {
+	if strings.TrimSpace(instanceId) == "" {
+		return nil, errors.New("cloud instance ID must not be empty")
+	}
+	if err := ValidateCloudInstanceFields(extraFields); err != nil {
+		return nil, err
+	}
+
+	query := func(selection string) string {
+		return fmt.Sprintf(`
+	query($id: String!) {
+		vpc {
+			instance {
+				get(id: $id) {
+					%s
+				}
+			}
+		}
+	}
+	`, selection)
+	}
+
+	variables := map[string]interface{}{
+		"id": instanceId,
+	}
+
+	var response struct {
+		Data struct {
+			VPC struct {
+				Instance struct {
+					Get map[string]interface{} `json:"get"`
+				} `json:"instance"`
+			} `json:"vpc"`
+		} `json:"data"`
+	}
+
+	if err := c.executeCloudInstanceQuery(query, extraFields, variables, &response); err != nil {
+		return nil, fmt.Errorf("failed to get cloud instance %s: %w", instanceId, err)
+	}
+	if response.Data.VPC.Instance.Get == nil {
+		return nil, fmt.Errorf("cloud instance %s not found", instanceId)
+	}
+
+	return response.Data.VPC.Instance.Get, nil
 }
 
 // GetVpsServersList returns a list of VPS servers
@@ -516,28 +1645,8 @@ func (c *Client) GetVpsServersList() (map[string]interface{}, error) {
 	return response, nil
 }
 
-// GetVpsServersStatus returns status information about VPS servers
-func (c *Client) GetVpsServersStatus() (map[string]interface{}, error) {
-	// Create a stub for VPS servers status for compatibility
-	response := map[string]interface{}{
-		"data": map[string]interface{}{
-			"vps": map[string]interface{}{
-				"server": map[string]interface{}{
-					"pagination": map[string]interface{}{
-						"items": []interface{}{},
-						"count": float64(0),
-					},
-				},
-			},
-		},
-	}
-
-	query := `
-	query {
-		vps {
-			server {
-				pagination(perPage: 100) {
-					items {
+// vpsServerStatusFields are the fields selected for each VPS server in status queries
+const vpsServerStatusFields = `
 						serverId
 						name
 						status
@@ -548,25 +1657,154 @@ func (c *Client) GetVpsServersStatus() (map[string]interface{}, error) {
 							ramGb
 							cores
 						}
-					}
+					`
+
+// vpsServerDateFields are the creation and paid-until dates of a VPS server,
+// selected until the API rejects them
+const vpsServerDateFields = `	createdAt
+						paidTill
+					`
+
+// vpsServerImageField is the image name of a VPS server, selected until the API rejects it
+const vpsServerImageField = `	imageName
+					`
+
+// vpsServerSelection returns the fields selected for each VPS server
+func vpsServerSelection(withImage, withDates bool) string {
+	selection := vpsServerStatusFields
+	if withImage {
+		selection += vpsServerImageField
+	}
+	if withDates {
+		selection += vpsServerDateFields
+	}
+	return selection
+}
+
+// executeVPSServerQuery runs the VPS server query that query builds from a field
+// selection. The image name and server dates are selected until the API rejects
+// the fields.
+func (c *Client) executeVPSServerQuery(query func(selection string) string, variables map[string]interface{}, result interface{}) error {
+	c.mutex.Lock()
+	withImage := !c.vpsImageUnsupported
+	withDates := !c.vpsDatesUnsupported
+	c.mutex.Unlock()
+
+	// Optional fields the API rejects are dropped and the query is retried
+	for {
+		err := c.executeQuery(vpsGraphQLEndpoint, query(vpsServerSelection(withImage, withDates)), variables, result)
+		imageRejected := withImage && rejectsField(err, "imageName")
+		datesRejected := withDates && rejectsField(err, "createdAt", "paidTill")
+		if !imageRejected && !datesRejected {
+			return err
+		}
+
+		c.mutex.Lock()
+		if imageRejected {
+			// The API does not report image names, stop asking for them
+			log.Printf("Warning: VPS server image names are not available, querying servers without them: %v", err)
+			c.vpsImageUnsupported = true
+			withImage = false
+		}
+		if datesRejected {
+			// The API does not report server dates, stop asking for them
+			log.Printf("Warning: VPS server dates are not available, querying servers without them: %v", err)
+			c.vpsDatesUnsupported = true
+			withDates = false
+		}
+		c.mutex.Unlock()
+	}
+}
+
+// GetVpsServersStatus returns status information about VPS servers
+func (c *Client) GetVpsServersStatus() (map[string]interface{}, error) {
+	variables := map[string]interface{}{
+		"perPage": c.pageSize("vpsServersStatus", 100),
+	}
+
+	query := func(selection string) string {
+		return `
+	query($perPage: Int) {
+		vps {
+			server {
+				pagination(perPage: $perPage) {
+					items {` + selection + `}
 					count
 				}
 			}
 		}
 	}
 	`
+	}
 
-	// Try to execute the query but return a stub if an error occurs
 	var result map[string]interface{}
-	err := c.executeQuery(vpsGraphQLEndpoint, query, nil, &result)
-	if err == nil && result != nil {
-		response = result
-	} else {
-		// Log the error but don't return it, using the stub instead
-		fmt.Printf("Warning: Failed to get VPS servers status, using stub data: %v\n", err)
+	err := c.executeVPSServerQuery(query, variables, &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get VPS servers status: %w", err)
 	}
 
-	return response, nil
+	return result, nil
+}
+
+// VPSServerRef identifies a single VPS server
+type VPSServerRef struct {
+	ServerID int
+	RegionID string
+}
+
+// Validate checks that the server ID and region are set
+func (r VPSServerRef) Validate() error {
+	if r.ServerID <= 0 {
+		return fmt.Errorf("invalid VPS server ID %d", r.ServerID)
+	}
+	if r.RegionID == "" {
+		return fmt.Errorf("VPS server %d has no region", r.ServerID)
+	}
+	return nil
+}
+
+// GetVPSServer returns the status of a single VPS server, with the same fields
+// as the items of GetVpsServersStatus. Any failure is returned so it can be reported for the server.
+func (c *Client) GetVPSServer(serverId int, regionId string) (map[string]interface{}, error) {
+	if err := (VPSServerRef{ServerID: serverId, RegionID: regionId}).Validate(); err != nil {
+		return nil, err
+	}
+
+	query := func(selection string) string {
+		return `
+	query($serverId: Int!, $regionId: String!) {
+		vps {
+			server {
+				get(input: { serverId: $serverId, regionId: $regionId }) {` + selection + `}
+			}
+		}
+	}
+	`
+	}
+
+	variables := map[string]interface{}{
+		"serverId": serverId,
+		"regionId": regionId,
+	}
+
+	var response struct {
+		Data struct {
+			VPS struct {
+				Server struct {
+					Get map[string]interface{} `json:"get"`
+				} `json:"server"`
+			} `json:"vps"`
+		} `json:"data"`
+	}
+
+	if err := c.executeVPSServerQuery(query, variables, &response); err != nil {
+		return nil, fmt.Errorf("failed to get VPS server %d: %w", serverId, err)
+	}
+	if response.Data.VPS.Server.Get == nil {
+		return nil, fmt.Errorf("VPS server %d not found in region %s", serverId, regionId)
+	}
+
+	return response.Data.VPS.Server.Get, nil
 }
 
 // GetVpsBackups returns information about VPS server backups
@@ -625,25 +1863,15 @@ func (c *Client) GetVpsIpsLogs(serverId int, regionId string) (map[string]interf
 
 // GetK8SClusters returns information about Kubernetes clusters
 func (c *Client) GetK8SClusters() (map[string]interface{}, error) {
-	// Create a stub for K8S clusters for compatibility
-	response := map[string]interface{}{
-		"data": map[string]interface{}{
-			"k8saas": map[string]interface{}{
-				"cluster": map[string]interface{}{
-					"pagination": map[string]interface{}{
-						"count": float64(0),
-						"items": []interface{}{},
-					},
-				},
-			},
-		},
+	variables := map[string]interface{}{
+		"perPage": c.pageSize("k8sClusters", 100),
 	}
 
 	query := `
-	query {
+	query($perPage: Int) {
 		k8saas {
 			cluster {
-				pagination(perPage: 100) {
+				pagination(perPage: $perPage) {
 					count
 					items {
 						_id
@@ -654,6 +1882,7 @@ func (c *Client) GetK8SClusters() (map[string]interface{}, error) {
 						regionId
 						nodeCount
 						masterCount
+						%s
 						clusterTemplate {
 							name
 						}
@@ -661,10 +1890,12 @@ func (c *Client) GetK8SClusters() (map[string]interface{}, error) {
 							_id
 							name
 							nodeCount
+							%s
 							status
 							flavorDetailed {
 								vcpus
 								ram
+								%s
 							}
 						}
 					}
@@ -674,17 +1905,63 @@ func (c *Client) GetK8SClusters() (map[string]interface{}, error) {
 	}
 	`
 
-	// Try to execute the query but return a stub if an error occurs
+	c.mutex.Lock()
+	withHealth := !c.clusterHealthUnsupported
+	withReadyNodes := !c.readyNodesUnsupported
+	withDisk := !c.flavorDiskUnsupported
+	c.mutex.Unlock()
+
+	// Optional fields the API rejects are dropped and the query is retried
 	var result map[string]interface{}
-	err := c.executeQuery(k8saasGraphQLEndpoint, query, nil, &result)
-	if err == nil && result != nil {
-		response = result
-	} else {
-		// Log the error but don't return it, using the stub instead
-		fmt.Printf("Warning: Failed to get K8S clusters, using stub data: %v\n", err)
+	var err error
+	for {
+		healthField, readyNodesField, diskField := "", "", ""
+		if withHealth {
+			healthField = "healthStatus"
+		}
+		if withReadyNodes {
+			readyNodesField = "readyNodeCount"
+		}
+		if withDisk {
+			diskField = "disk"
+		}
+
+		result = nil
+		err = c.executeQuery(k8saasGraphQLEndpoint, fmt.Sprintf(query, healthField, readyNodesField, diskField), variables, &result)
+		healthRejected := withHealth && rejectsField(err, healthField)
+		readyNodesRejected := withReadyNodes && rejectsField(err, readyNodesField)
+		diskRejected := withDisk && rejectsField(err, `"disk"`)
+		if !healthRejected && !readyNodesRejected && !diskRejected {
+			break
+		}
+
+		c.mutex.Lock()
+		if healthRejected {
+			// The API does not report cluster health, stop asking for it
+			log.Printf("Warning: K8S cluster health is not available, querying clusters without it: %v", err)
+			c.clusterHealthUnsupported = true
+			withHealth = false
+		}
+		if readyNodesRejected {
+			// The API does not report node readiness, stop asking for it
+			log.Printf("Warning: K8S node group readiness is not available, querying clusters without it: %v", err)
+			c.readyNodesUnsupported = true
+			withReadyNodes = false
+		}
+		if diskRejected {
+			// The API does not report flavor disk sizes, stop asking for them
+			log.Printf("Warning: K8S node group disk size is not available, querying clusters without it: %v", err)
+			c.flavorDiskUnsupported = true
+			withDisk = false
+		}
+		c.mutex.Unlock()
 	}
 
-	return response, nil
+	if err != nil {
+		return nil, fmt.Errorf("failed to get K8S clusters: %w", err)
+	}
+
+	return result, nil
 }
 
 // GetK8SAccountInfo returns account information from k8saas
@@ -729,22 +2006,88 @@ func (c *Client) GetK8SAccountInfo() (map[string]interface{}, error) {
 
 // GetLBaaSLoadBalancers retrieves load balancer information from LBaaS API
 func (c *Client) GetLBaaSLoadBalancers() (map[string]interface{}, error) {
-	// Create a stub for LBaaS load balancers for compatibility
-	// since the API structure has changed significantly
-	response := map[string]interface{}{
-		"data": map[string]interface{}{
-			"lbaas": map[string]interface{}{
-				"loadBalancer": map[string]interface{}{
-					"pagination": map[string]interface{}{
-						"count": float64(0),
-						"items": []interface{}{},
-					},
-				},
-			},
-		},
+	variables := map[string]interface{}{
+		"perPage": c.pageSize("lbaas", 100),
 	}
 
-	return response, nil
+	query := `
+	query($perPage: Int) {
+		lbaas {
+			loadBalancer {
+				pagination(perPage: $perPage) {
+					count
+					items {
+						_id
+						name
+						regionId
+						vipAddress
+						provisioningStatus%s
+						floatingIpAddress
+						flavorName
+						cluster {
+							name
+						}
+						listeners {
+							_id
+						}
+						pools {
+							_id
+						}
+						members {
+							_id%s
+						}
+					}
+				}
+			}
+		}
+	}
+	`
+
+	c.mutex.Lock()
+	withDates := !c.lbaasDatesUnsupported
+	withMemberStatus := !c.memberStatusUnsupported
+	c.mutex.Unlock()
+
+	// Optional fields the API rejects are dropped and the query is retried
+	var result map[string]interface{}
+	var err error
+	for {
+		datesField, memberStatusField := "", ""
+		if withDates {
+			datesField = "\n\t\t\t\t\t\tcreatedAt\n\t\t\t\t\t\tupdatedAt"
+		}
+		if withMemberStatus {
+			memberStatusField = "\n\t\t\t\t\t\t\toperatingStatus"
+		}
+
+		result = nil
+		err = c.executeQuery(lbaasGraphQLEndpoint, fmt.Sprintf(query, datesField, memberStatusField), variables, &result)
+		datesRejected := withDates && rejectsField(err, "createdAt", "updatedAt")
+		memberStatusRejected := withMemberStatus && rejectsField(err, "operatingStatus")
+		if !datesRejected && !memberStatusRejected {
+			break
+		}
+
+		c.mutex.Lock()
+		if datesRejected {
+			// The API does not report load balancer timestamps, stop asking for them
+			log.Printf("Warning: LBaaS load balancer timestamps are not available, querying load balancers without them: %v", err)
+			c.lbaasDatesUnsupported = true
+			withDates = false
+		}
+		if memberStatusRejected {
+			// The API does not report member health, stop asking for it
+			log.Printf("Warning: LBaaS member health is not available, querying load balancers without it: %v", err)
+			c.memberStatusUnsupported = true
+			withMemberStatus = false
+		}
+		c.mutex.Unlock()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get LBaaS load balancers: %w", err)
+	}
+
+	return result, nil
 }
 
 // AccountUserData represents user data from the account API
@@ -817,25 +2160,15 @@ func (c *Client) TestAuth() (*AccountUserData, error) {
 
 // GetK8SProjects returns information about Kubernetes projects
 func (c *Client) GetK8SProjects() (map[string]interface{}, error) {
-	// Create a stub for K8S projects for compatibility
-	response := map[string]interface{}{
-		"data": map[string]interface{}{
-			"k8saas": map[string]interface{}{
-				"project": map[string]interface{}{
-					"pagination": map[string]interface{}{
-						"items": []interface{}{},
-						"count": float64(0),
-					},
-				},
-			},
-		},
+	variables := map[string]interface{}{
+		"perPage": c.pageSize("k8sProjects", 100),
 	}
 
 	query := `
-	query {
+	query($perPage: Int) {
 		k8saas {
 			project {
-				pagination(perPage: 100) {
+				pagination(perPage: $perPage) {
 					items {
 						_id
 						projectId
@@ -860,15 +2193,11 @@ func (c *Client) GetK8SProjects() (map[string]interface{}, error) {
 	}
 	`
 
-	// Try to execute the query but return a stub if an error occurs
 	var result map[string]interface{}
-	err := c.executeQuery(k8saasGraphQLEndpoint, query, nil, &result)
-	if err == nil && result != nil {
-		response = result
-	} else {
-		// Log the error but don't return it, using the stub instead
-		fmt.Printf("Warning: Failed to get K8S projects, using stub data: %v\n", err)
+	err := c.executeQuery(k8saasGraphQLEndpoint, query, variables, &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get K8S projects: %w", err)
 	}
 
-	return response, nil
+	return result, nil
 }
@@ -0,0 +1,475 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// redirectTransport sends every request to target, whatever endpoint it was made for
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	req.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newTestClient returns a client whose requests to all endpoints are served by handler
+func newTestClient(t *testing.T, handler http.HandlerFunc, options ClientOptions) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	options.Transport = redirectTransport{target: target}
+	c := NewWithOptions("test-token", options)
+	t.Cleanup(c.Close)
+	return c
+}
+
+// recordedQuery is a GraphQL query received by a test server
+type recordedQuery struct {
+	path   string
+	query  string
+	header http.Header
+}
+
+// queryRecorder records the queries received by a test server and answers them with respond
+type queryRecorder struct {
+	mutex   sync.Mutex
+	queries []recordedQuery
+	respond func(n int, query string) (status int, body string)
+}
+
+func (r *queryRecorder) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		var request GraphQLRequest
+		if err := json.Unmarshal(body, &request); err != nil {
+			t.Errorf("invalid request body: %v", err)
+			return
+		}
+
+		r.mutex.Lock()
+		r.queries = append(r.queries, recordedQuery{path: req.URL.Path, query: request.Query, header: req.Header.Clone()})
+		n := len(r.queries)
+		r.mutex.Unlock()
+
+		status, response := r.respond(n, request.Query)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}
+}
+
+func (r *queryRecorder) count() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return len(r.queries)
+}
+
+func TestGetDomainsReturnsErrors(t *testing.T) {
+	recorder := &queryRecorder{respond: func(int, string) (int, string) {
+		return http.StatusOK, `{"errors":[{"message":"internal server error"}]}`
+	}}
+	c := newTestClient(t, recorder.handler(t), ClientOptions{})
+
+	domains, err := c.GetDomains(nil, false)
+	if !errors.Is(err, ErrGraphQL) {
+		t.Fatalf("GetDomains() error = %v, want ErrGraphQL", err)
+	}
+	if domains != nil {
+		t.Errorf("GetDomains() = %+v, want nil on error", domains)
+	}
+	// Errors that do not name a whois field keep whois in the query
+	if c.domainWhoisUnsupported {
+		t.Error("whois was disabled by an unrelated GraphQL error")
+	}
+	if got := recorder.count(); got != 1 {
+		t.Errorf("got %d queries, want 1 (no account query, no retry)", got)
+	}
+}
+
+func TestGetDomainsDropsRejectedWhois(t *testing.T) {
+	recorder := &queryRecorder{respond: func(_ int, query string) (int, string) {
+		if strings.Contains(query, "whois") {
+			return http.StatusOK, `{"errors":[{"message":"Cannot query field \"whois\" on type \"Domain\"."}]}`
+		}
+		return http.StatusOK, `{"data":{"domains":{"items":[{"name":"example.kz","status":"active","expiryDate":"2030-01-02"}]}}}`
+	}}
+	c := newTestClient(t, recorder.handler(t), ClientOptions{})
+
+	for i := 0; i < 2; i++ {
+		domains, err := c.GetDomains(nil, false)
+		if err != nil {
+			t.Fatalf("GetDomains() error = %v", err)
+		}
+		if items := domains.Data.Domains.Items; len(items) != 1 || items[0].Name != "example.kz" {
+			t.Fatalf("GetDomains() items = %+v", items)
+		}
+	}
+	// The rejected field is asked for once, the second call goes straight to the plain query
+	if got := recorder.count(); got != 3 {
+		t.Errorf("got %d queries, want 3", got)
+	}
+}
+
+func TestGetDomainsDropsRejectedDNS(t *testing.T) {
+	recorder := &queryRecorder{respond: func(_ int, query string) (int, string) {
+		if strings.Contains(query, "nameservers") {
+			return http.StatusOK, `{"errors":[{"message":"Cannot query field \"nameservers\" on type \"Domain\"."}]}`
+		}
+		return http.StatusOK, `{"data":{"domains":{"items":[{"name":"example.kz","status":"active","expiryDate":"2030-01-02"}]}}}`
+	}}
+	c := newTestClient(t, recorder.handler(t), ClientOptions{})
+
+	domains, err := c.GetDomains(nil, false)
+	if err != nil {
+		t.Fatalf("GetDomains() error = %v", err)
+	}
+	if items := domains.Data.Domains.Items; len(items) != 1 || items[0].Nameservers != nil || items[0].DNSSEC != nil {
+		t.Fatalf("GetDomains() items = %+v", items)
+	}
+	if !c.domainDNSUnsupported || c.domainWhoisUnsupported {
+		t.Errorf("DNS unsupported = %v, whois unsupported = %v, want only DNS dropped", c.domainDNSUnsupported, c.domainWhoisUnsupported)
+	}
+	if got := recorder.count(); got != 2 {
+		t.Errorf("got %d queries, want 2", got)
+	}
+	if retry := recorder.queries[1].query; strings.Contains(retry, "dnssec") || !strings.Contains(retry, "whois") {
+		t.Errorf("retried query %q should keep whois and drop the DNS fields", retry)
+	}
+}
+
+func TestGetDomainsAutoRenew(t *testing.T) {
+	recorder := &queryRecorder{respond: func(_ int, query string) (int, string) {
+		if strings.Contains(query, "autoRenew") {
+			return http.StatusOK, `{"errors":[{"message":"Cannot query field \"autoRenew\" on type \"Domain\"."}]}`
+		}
+		return http.StatusOK, `{"data":{"domains":{"items":[{"name":"example.kz","status":"active","expiryDate":"2030-01-02"}]}}}`
+	}}
+	c := newTestClient(t, recorder.handler(t), ClientOptions{})
+
+	// The field is not selected unless asked for
+	if _, err := c.GetDomains(nil, false); err != nil {
+		t.Fatalf("GetDomains() without auto-renew error = %v", err)
+	}
+	if got := recorder.count(); got != 1 {
+		t.Errorf("got %d queries without auto-renew, want 1", got)
+	}
+
+	// A rejected field is dropped and the query retried
+	domains, err := c.GetDomains(nil, true)
+	if err != nil {
+		t.Fatalf("GetDomains() with auto-renew error = %v", err)
+	}
+	if items := domains.Data.Domains.Items; len(items) != 1 || items[0].AutoRenew != nil {
+		t.Fatalf("GetDomains() items = %+v", items)
+	}
+	if !c.domainAutoRenewUnsupported {
+		t.Error("rejected autoRenew field is still queried")
+	}
+	if got := recorder.count(); got != 3 {
+		t.Errorf("got %d queries, want 3", got)
+	}
+}
+
+func TestExecuteQueryHTMLResponse(t *testing.T) {
+	page := "<html>\n  <head><title>Maintenance</title></head>\n  <body>We will be back soon</body>\n</html>"
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(page))
+	}, ClientOptions{})
+
+	_, err := c.ExecuteQuery("/account/graphql", "query { account { current { id } } }", nil)
+	if !errors.Is(err, ErrNonJSONResponse) {
+		t.Fatalf("ExecuteQuery() error = %v, want ErrNonJSONResponse", err)
+	}
+	// The snippet has its whitespace collapsed
+	if want := "<html> <head><title>Maintenance</title></head> <body>We will be back soon</body> </html>"; !strings.Contains(err.Error(), want) {
+		t.Errorf("error %q does not contain the body snippet %q", err, want)
+	}
+	if !strings.Contains(err.Error(), "text/html") {
+		t.Errorf("error %q does not name the content type", err)
+	}
+}
+
+func TestIsNonJSON(t *testing.T) {
+	tests := []struct {
+		contentType, body string
+		want              bool
+	}{
+		{"application/json", `{"data":{}}`, false},
+		{"application/json; charset=utf-8", `{"data":{}}`, false},
+		{"text/html", `<html></html>`, true},
+		{"text/html", `{"data":{}}`, true},
+		{"text/plain", `{"data":{}}`, false},
+		{"text/plain", ` <!DOCTYPE html>`, true},
+		{"", `<html></html>`, true},
+		{"", `{"data":{}}`, false},
+	}
+	for _, tt := range tests {
+		if got := isNonJSON(tt.contentType, []byte(tt.body)); got != tt.want {
+			t.Errorf("isNonJSON(%q, %q) = %v, want %v", tt.contentType, tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestRecordRateLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		remaining string
+		reset     string
+		want      RateLimit
+		recorded  bool
+	}{
+		{"absolute reset", "42", "2000000000", RateLimit{Remaining: 42, Reset: time.Unix(2000000000, 0)}, true},
+		{"relative reset", "0", "60", RateLimit{Remaining: 0, Reset: time.Now().Add(60 * time.Second)}, true},
+		{"no reset", "7", "", RateLimit{Remaining: 7}, true},
+		{"no headers", "", "", RateLimit{}, false},
+		{"invalid remaining", "many", "60", RateLimit{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if tt.remaining != "" {
+					w.Header().Set("X-RateLimit-Remaining", tt.remaining)
+				}
+				if tt.reset != "" {
+					w.Header().Set("X-RateLimit-Reset", tt.reset)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"data":{}}`))
+			}, ClientOptions{})
+
+			if _, err := c.ExecuteQuery("/account/graphql", "query { account { current { id } } }", nil); err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+			limit, recorded := c.RateLimits()["account"]
+			if recorded != tt.recorded {
+				t.Fatalf("rate limit recorded = %v, want %v", recorded, tt.recorded)
+			}
+			if limit.Remaining != tt.want.Remaining {
+				t.Errorf("Remaining = %v, want %v", limit.Remaining, tt.want.Remaining)
+			}
+			// Relative resets are converted at response time, so allow some slack
+			if diff := limit.Reset.Sub(tt.want.Reset); diff < -5*time.Second || diff > 5*time.Second {
+				t.Errorf("Reset = %v, want %v", limit.Reset, tt.want.Reset)
+			}
+		})
+	}
+}
+
+func TestAuthHeaderMode(t *testing.T) {
+	tests := []struct {
+		name, mode, token, authorization string
+	}{
+		{"token", AuthHeaderToken, "test-token", ""},
+		{"bearer", AuthHeaderBearer, "", "Bearer test-token"},
+		{"both", AuthHeaderBoth, "test-token", "Bearer test-token"},
+		{"default", "", "test-token", "Bearer test-token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &queryRecorder{respond: func(int, string) (int, string) {
+				return http.StatusOK, `{"data":{}}`
+			}}
+			c := newTestClient(t, recorder.handler(t), ClientOptions{AuthHeaderMode: tt.mode})
+
+			if _, err := c.ExecuteQuery("/account/graphql", "query { account { current { id } } }", nil); err != nil {
+				t.Fatalf("ExecuteQuery() error = %v", err)
+			}
+			if got := recorder.count(); got != 1 {
+				t.Fatalf("got %d queries, want 1", got)
+			}
+			header := recorder.queries[0].header
+			if got := header.Get("X-User-Token"); got != tt.token {
+				t.Errorf("X-User-Token = %q, want %q", got, tt.token)
+			}
+			if got := header.Get("Authorization"); got != tt.authorization {
+				t.Errorf("Authorization = %q, want %q", got, tt.authorization)
+			}
+		})
+	}
+}
+
+func TestValidateAuthHeaderMode(t *testing.T) {
+	for _, mode := range []string{"", AuthHeaderToken, AuthHeaderBearer, AuthHeaderBoth} {
+		if err := ValidateAuthHeaderMode(mode); err != nil {
+			t.Errorf("ValidateAuthHeaderMode(%q) = %v, want nil", mode, err)
+		}
+	}
+	if err := ValidateAuthHeaderMode("basic"); err == nil {
+		t.Error(`ValidateAuthHeaderMode("basic") = nil, want an error`)
+	}
+}
+
+func TestOptionalFieldRejection(t *testing.T) {
+	tests := []struct {
+		name      string
+		call      func(c *Client) error
+		supported func(c *Client) bool
+		rejection string
+	}{
+		{
+			"account plan",
+			func(c *Client) error { _, err := c.GetAccountBalance(); return err },
+			func(c *Client) bool { return !c.planUnsupported },
+			`Cannot query field \"plan\" on type \"Account\".`,
+		},
+		{
+			"bank card list",
+			func(c *Client) error { _, err := c.GetBankCards(); return err },
+			func(c *Client) bool { return !c.cardListUnsupported },
+			`Cannot query field \"bankCards\" on type \"AccountInfo\".`,
+		},
+		{
+			"service type",
+			func(c *Client) error { _, err := c.GetProjects(nil, 0); return err },
+			func(c *Client) bool { return !c.serviceTypeUnsupported },
+			`Cannot query field \"type\" on type \"Service\".`,
+		},
+		{
+			"cloud instance fixed IPs",
+			func(c *Client) error { _, err := c.GetCloudInstances(nil); return err },
+			func(c *Client) bool { return !c.fixedIPsUnsupported },
+			`Cannot query field \"fixedIpsArray\" on type \"Instance\".`,
+		},
+		{
+			"VPS server dates",
+			func(c *Client) error { _, err := c.GetVpsServersStatus(); return err },
+			func(c *Client) bool { return !c.vpsDatesUnsupported },
+			`Cannot query field \"paidTill\" on type \"Server\".`,
+		},
+		{
+			"cloud instance image",
+			func(c *Client) error { _, err := c.GetCloudInstances(nil); return err },
+			func(c *Client) bool { return !c.cloudImageUnsupported },
+			`Cannot query field \"imageName\" on type \"Instance\".`,
+		},
+		{
+			"VPS server image",
+			func(c *Client) error { _, err := c.GetVpsServersStatus(); return err },
+			func(c *Client) bool { return !c.vpsImageUnsupported },
+			`Cannot query field \"imageName\" on type \"Server\".`,
+		},
+		{
+			"LBaaS timestamps",
+			func(c *Client) error { _, err := c.GetLBaaSLoadBalancers(); return err },
+			func(c *Client) bool { return !c.lbaasDatesUnsupported },
+			`Cannot query field \"updatedAt\" on type \"LoadBalancer\".`,
+		},
+		{
+			"LBaaS member health",
+			func(c *Client) error { _, err := c.GetLBaaSLoadBalancers(); return err },
+			func(c *Client) bool { return !c.memberStatusUnsupported },
+			`Cannot query field \"operatingStatus\" on type \"Member\".`,
+		},
+		{
+			"K8S cluster health",
+			func(c *Client) error { _, err := c.GetK8SClusters(); return err },
+			func(c *Client) bool { return !c.clusterHealthUnsupported },
+			`Cannot query field \"healthStatus\" on type \"Cluster\".`,
+		},
+		{
+			"K8S node readiness",
+			func(c *Client) error { _, err := c.GetK8SClusters(); return err },
+			func(c *Client) bool { return !c.readyNodesUnsupported },
+			`Cannot query field \"readyNodeCount\" on type \"NodeGroup\".`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Errors that do not name the field keep it in the query
+			recorder := &queryRecorder{respond: func(n int, _ string) (int, string) {
+				if n == 1 {
+					return http.StatusOK, `{"errors":[{"message":"permission denied"}]}`
+				}
+				return http.StatusOK, `{"data":{}}`
+			}}
+			c := newTestClient(t, recorder.handler(t), ClientOptions{})
+			if err := tt.call(c); !errors.Is(err, ErrGraphQL) {
+				t.Errorf("unrelated error: got %v, want ErrGraphQL", err)
+			}
+			if !tt.supported(c) {
+				t.Error("unrelated error disabled the optional field")
+			}
+			if got := recorder.count(); got != 1 {
+				t.Errorf("unrelated error: got %d queries, want 1", got)
+			}
+
+			// Errors that name the field drop it and retry the query
+			recorder = &queryRecorder{respond: func(n int, _ string) (int, string) {
+				if n == 1 {
+					return http.StatusOK, `{"errors":[{"message":"` + tt.rejection + `"}]}`
+				}
+				return http.StatusOK, `{"data":{}}`
+			}}
+			c = newTestClient(t, recorder.handler(t), ClientOptions{})
+			if err := tt.call(c); err != nil {
+				t.Errorf("rejected field: got %v, want the query retried without it", err)
+			}
+			if tt.supported(c) {
+				t.Error("rejected field is still queried")
+			}
+			if got := recorder.count(); got != 2 {
+				t.Errorf("rejected field: got %d queries, want 2", got)
+			}
+		})
+	}
+}
+
+func TestCloudInstanceSelectionImageName(t *testing.T) {
+	extra := []string{"imageName", "keyName"}
+	if got := strings.Count(cloudInstanceSelection(extra, true, true), "imageName"); got != 1 {
+		t.Errorf("imageName selected %d times, want once", got)
+	}
+	// A rejected image name is not selected again as an extra field
+	selection := cloudInstanceSelection(extra, false, true)
+	if strings.Contains(selection, "imageName") || !strings.Contains(selection, "keyName") {
+		t.Errorf("selection without image = %q", selection)
+	}
+}
+
+func TestVerifyEndpointsSkipsDisabledServices(t *testing.T) {
+	recorder := &queryRecorder{respond: func(int, string) (int, string) {
+		return http.StatusOK, `{"data":{"__typename":"Query"}}`
+	}}
+	c := newTestClient(t, recorder.handler(t), ClientOptions{DisabledServices: []string{"lbaas", "k8saas"}})
+
+	statuses := c.VerifyEndpoints()
+	if len(statuses) != len(Services) {
+		t.Fatalf("got %d statuses, want %d", len(statuses), len(Services))
+	}
+	for _, status := range statuses {
+		disabled := status.Name == "lbaas" || status.Name == "k8saas"
+		if status.Disabled != disabled {
+			t.Errorf("%s disabled = %v, want %v", status.Name, status.Disabled, disabled)
+		}
+		if status.Err != nil {
+			t.Errorf("%s error = %v", status.Name, status.Err)
+		}
+	}
+	if got := recorder.count(); got != len(Services)-2 {
+		t.Errorf("got %d queries, want %d (disabled services not queried)", got, len(Services)-2)
+	}
+}
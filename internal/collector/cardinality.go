@@ -0,0 +1,60 @@
+package collector
+
+import (
+	"log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// defaultMaxSeriesPerMetric is the series limit per metric family used when none is configured
+const defaultMaxSeriesPerMetric = 10000
+
+// cardinalityGuard limits the number of series exposed per metric family in a single scrape
+type cardinalityGuard struct {
+	limit  int
+	counts map[string]int  // series per metric family in the current scrape
+	logged map[string]bool // families for which the limit hit has been logged
+	hits   *prometheus.CounterVec
+}
+
+// newCardinalityGuard creates a guard with the given per-family limit
+func newCardinalityGuard(limit int) *cardinalityGuard {
+	if limit <= 0 {
+		limit = defaultMaxSeriesPerMetric
+	}
+
+	return &cardinalityGuard{
+		limit:  limit,
+		counts: make(map[string]int),
+		logged: make(map[string]bool),
+		hits: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "pskz",
+				Name:      "cardinality_limit_hit_total",
+				Help:      "Number of series dropped because their metric family exceeded the series limit",
+			},
+			[]string{"metric"},
+		),
+	}
+}
+
+// begin resets the per-scrape series counts
+func (g *cardinalityGuard) begin() {
+	g.counts = make(map[string]int)
+}
+
+// allow reports whether m may be exposed without exceeding the limit of its family
+func (g *cardinalityGuard) allow(m prometheus.Metric) bool {
+	name := MetricName(m.Desc())
+	if g.counts[name] < g.limit {
+		g.counts[name]++
+		return true
+	}
+
+	g.hits.WithLabelValues(name).Inc()
+	if !g.logged[name] {
+		log.Printf("Metric %s exceeded the limit of %d series, dropping additional series", name, g.limit)
+		g.logged[name] = true
+	}
+	return false
+}
@@ -1,38 +1,305 @@
 package collector
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
+	"unicode"
 
 	"github.com/atlet99/pscloud-exporter/internal/client"
 
 	kitlog "github.com/go-kit/log"
+	"github.com/go-kit/log/level"
 	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sync/singleflight"
 )
 
+// expiryDateLayouts lists the date layouts accepted for domain expiry dates, in order of preference
+var expiryDateLayouts = []string{
+	"2006-01-02",
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+}
+
+// parseExpiryDate parses a domain expiry date using the known layouts,
+// falling back to a Unix timestamp in seconds
+func parseExpiryDate(value string) (time.Time, error) {
+	for _, layout := range expiryDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognized date format: %q", value)
+}
+
+// stubMetricPrefixes lists metric name prefixes whose data sources are
+// currently stubbed in the client and therefore never carry real data
+var stubMetricPrefixes = []string{
+	"pskz_domain_counters",
+}
+
+// DataSource reports whether the given metric is backed by a real API call ("api")
+// or by a stub implementation in the client ("stub")
+func DataSource(metricName string) string {
+	for _, prefix := range stubMetricPrefixes {
+		if strings.HasPrefix(metricName, prefix) {
+			return "stub"
+		}
+	}
+	return "api"
+}
+
+// parseTimeValue parses a timestamp returned by the API, which is either a
+// date string or a Unix timestamp number in seconds or milliseconds
+func parseTimeValue(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return time.Time{}, false
+		}
+		t, err := parseExpiryDate(v)
+		return t, err == nil
+	case float64:
+		if v <= 0 {
+			return time.Time{}, false
+		}
+		if v > 1e12 {
+			return time.UnixMilli(int64(v)), true
+		}
+		return time.Unix(int64(v), 0), true
+	}
+	return time.Time{}, false
+}
+
+// CollectorNames are the data sources a scrape is made of, in scrape order
+var CollectorNames = []string{
+	"balance",
+	"account_verification",
+	"bank_cards",
+	"domain_counters",
+	"domains",
+	"domain_prices",
+	"dns_records",
+	"projects",
+	"invoices",
+	"cloud_resources",
+	"cloud_instances",
+	"vps_servers",
+	"cloud_servers",
+	"k8s_clusters",
+	"k8s_projects",
+	"lbaas",
+}
+
+// collectorServices maps collectors to the PS.KZ service they query. Collectors
+// of a service disabled in the client are skipped. cloud_servers queries both the
+// cloud and the VPS service and checks them itself.
+var collectorServices = map[string]string{
+	"balance":              "account",
+	"account_verification": "account",
+	"bank_cards":           "k8saas",
+	"domain_counters":      "domains",
+	"domains":              "domains",
+	"domain_prices":        "domains",
+	"dns_records":          "domains",
+	"projects":             "account",
+	"invoices":             "account",
+	"cloud_resources":      "cloud",
+	"cloud_instances":      "cloud",
+	"vps_servers":          "vps",
+	"k8s_clusters":         "k8saas",
+	"k8s_projects":         "k8saas",
+	"lbaas":                "lbaas",
+}
+
+// ValidateCollectorName checks that name is one of CollectorNames
+func ValidateCollectorName(name string) error {
+	for _, known := range CollectorNames {
+		if name == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown collector %q (expected one of %s)", name, strings.Join(CollectorNames, ", "))
+}
+
+// htmlResponseError is the error type recorded when the API answered with a non-JSON page
+const htmlResponseError = "html_response"
+
+// Options contains optional settings for the exporter
+type Options struct {
+	ServiceID string   // Service ID for VPC and VPS API requests
+	DNSZones  []string // DNS zones whose records are exported
+	// DomainStatuses limits the exported domains to these statuses; empty means all
+	DomainStatuses []string
+	// DomainExpiryBuckets are the windows in days pskz_domains_expiring_within_count counts domains for (default 30, 60, 90)
+	DomainExpiryBuckets []int
+	// CloudInstanceFields are additional instance fields exposed as labels of pskz_cloud_instance_fields_info
+	CloudInstanceFields []string
+	// VPSServers and CloudInstances, if set, limit the VPS and cloud instance metrics
+	// to these resources, each fetched individually instead of listing all
+	VPSServers     []client.VPSServerRef
+	CloudInstances []string
+	// ProjectLabel selects the value of the project label: id, name, domain or domain-id (default)
+	ProjectLabel string
+	// Debounce maps metric family names to the number of consecutive scrapes
+	// a new value must be observed before it is exposed
+	Debounce map[string]int
+	// Relabel rules change the labels of gauges and counters before they are exposed
+	Relabel []RelabelRule
+	// MaxSeriesPerMetric limits the number of series per metric family in a scrape (default 10000)
+	MaxSeriesPerMetric int
+	// AccountVerification enables collection of the account verification expiry
+	AccountVerification bool
+	// BankCards enables collection of the account's payment card count and expiry dates
+	BankCards bool
+	// DomainPrices enables collection of domain zone prices
+	DomainPrices bool
+	// DomainAutoRenew enables the domain auto-renew status metric
+	DomainAutoRenew bool
+	// RegionNames maps region IDs to display names used when the API provides none
+	RegionNames map[string]string
+	// ScrapeErrorWindow is the number of scrapes pskz_scrape_error_ratio is computed over (default 10)
+	ScrapeErrorWindow int
+	// SuccessPolicy decides pskz_scrape_success from the data sources fetched in a
+	// scrape: SuccessPolicyAny (default) or SuccessPolicyAll
+	SuccessPolicy string
+	// DeltaExposition keeps the series of data groups whose API response is
+	// unchanged since the previous scrape instead of recomputing them (experimental)
+	DeltaExposition bool
+	// DropZeroSeries omits zero-valued series of the metric families listed in zeroDropMetrics
+	DropZeroSeries bool
+	// MetricTimestamps exposes every series with the time its data was fetched
+	MetricTimestamps bool
+	// Only, if set, restricts scrapes to the named collector (one of CollectorNames),
+	// overriding the optional collector settings; meant for debugging
+	Only string
+	// Version and Build identify the exporter binary in pskz_exporter_info
+	Version string
+	Build   string
+	// TokenSource tells where the API token was configured, exposed in pskz_token_source_info
+	TokenSource string
+	// ConfigLoadedAt is when the configuration in use was loaded, exposed as pskz_config_age_seconds
+	ConfigLoadedAt time.Time
+}
+
 // Exporter collects PS.KZ metrics
 type Exporter struct {
 	client    *client.Client
-	serviceID string // Service ID for VPC and VPS API requests
+	serviceID string   // Service ID for VPC and VPS API requests
+	dnsZones  []string // DNS zones whose records are exported
+
+	projectLabel        string                // strategy for the project label, see ProjectLabel in Options
+	domainStatuses      []string              // statuses of the domains to fetch, empty for all
+	domainExpiryBuckets []int                 // expiry windows in days, sorted ascending
+	vpsServers          []client.VPSServerRef // monitored VPS servers, empty for all
+	cloudInstances      []string              // monitored cloud instance IDs, empty for all
+	cloudInstanceFields []string              // additional cloud instance fields exposed as labels
+
+	// regionNames maps region IDs to display names used when the API provides none
+	regionNames map[string]string
+	// regions holds the display names of the regions seen in the current scrape
+	regions map[string]string
+
+	// Delta exposition state, see delta.go
+	deltaExposition   bool
+	groupMetrics      map[string][]*prometheus.GaugeVec
+	groupFingerprints map[string]uint64
+	groupRegions      map[string]map[string]string
+	groupsServed      map[string]int // data groups processed ("fresh") or kept ("cached") in the current scrape
+
+	// Metric timestamp state, see timestamps.go. groupFetched is read while
+	// metrics are sent and is therefore guarded by fetchedMutex.
+	metricTimestamps bool
+	groupDescs       map[*prometheus.Desc]string
+	groupFetched     map[string]time.Time
+	fetchedMutex     sync.Mutex
+
+	// Optional collectors
+	collectAccountVerification bool
+	collectBankCards           bool
+	collectDomainPrices        bool
+	collectDomainAutoRenew     bool
 
 	// Scrape metrics
-	scrapeDurationMetric  prometheus.Gauge
-	scrapeSuccessMetric   prometheus.Gauge
-	lastScrapeErrorMetric *prometheus.GaugeVec
+	scrapeDurationMetric   prometheus.Gauge
+	mutexWaitMetric        prometheus.Gauge
+	scrapeIntervalMetric   prometheus.Gauge
+	scrapeSuccessMetric    prometheus.Gauge
+	scrapeErrorRatioMetric prometheus.Gauge
+	scrapeAttemptsMetric   prometheus.Counter
+	scrapeSharedMetric     prometheus.Counter
+	scrapeSuccessesMetric  prometheus.Counter
+	lastScrapeErrorMetric  *prometheus.GaugeVec
+	monitoredErrorMetric   *prometheus.GaugeVec
+	schemaMissingMetric    *prometheus.GaugeVec
+	targetInfoMetric       *prometheus.GaugeVec
+	responseBytesMetric    *prometheus.HistogramVec
+	cacheServedMetric      *prometheus.GaugeVec
+	exporterInfoMetric     *prometheus.GaugeVec
+	tokenSourceMetric      *prometheus.GaugeVec
+	configAgeMetric        prometheus.Gauge
+	regionInfoMetric       *prometheus.GaugeVec
+	regionsInUseMetric     prometheus.Gauge
+	scrapeErrorsMetric     prometheus.Gauge
+	dataAgeMetric          *prometheus.GaugeVec
+
+	// API metrics
+	rateLimitRemainingMetric *prometheus.GaugeVec
+	rateLimitResetMetric     *prometheus.GaugeVec
+	circuitOpenMetric        *prometheus.GaugeVec
+	reauthDesc               *prometheus.Desc
 
 	// Balance metrics
-	prepayMetric  *prometheus.GaugeVec
-	creditMetric  *prometheus.GaugeVec
-	debtMetric    *prometheus.GaugeVec
-	bonusMetric   *prometheus.GaugeVec
-	blockedMetric *prometheus.GaugeVec
+	prepayMetric        *prometheus.GaugeVec
+	prepayChangeMetric  *prometheus.GaugeVec
+	creditMetric        *prometheus.GaugeVec
+	debtMetric          *prometheus.GaugeVec
+	bonusMetric         *prometheus.GaugeVec
+	blockedMetric       *prometheus.GaugeVec
+	creditOverdueMetric *prometheus.GaugeVec
+	accountPlanMetric   *prometheus.GaugeVec
+
+	// Account-level totals across regions and services
+	accountCoresMetric     *prometheus.GaugeVec
+	accountRAMMetric       *prometheus.GaugeVec
+	accountInstancesMetric *prometheus.GaugeVec
+
+	// Account metrics
+	verificationExpiryMetric *prometheus.GaugeVec
+	bankCardsMetric          *prometheus.GaugeVec
+	cardExpiryMetric         *prometheus.GaugeVec
 
 	// Domain metrics
 	domainExpiryMetric   *prometheus.GaugeVec
 	domainStatusMetric   *prometheus.GaugeVec
 	domainCountersMetric *prometheus.GaugeVec
+	domainDNSSECMetric   *prometheus.GaugeVec
+	domainNSCountMetric  *prometheus.GaugeVec
+
+	domainPriceMetric        *prometheus.GaugeVec
+	domainPriceDiffMetric    *prometheus.GaugeVec
+	domainAutoRenewMetric    *prometheus.GaugeVec
+	domainLastUpdateMetric   *prometheus.GaugeVec
+	domainLastTransferMetric *prometheus.GaugeVec
+	domainPrivacyMetric      *prometheus.GaugeVec
+	domainContactMetric      *prometheus.GaugeVec
+	domainsExpiringMetric    *prometheus.GaugeVec
+
+	// DNS metrics
+	dnsRecordInfoMetric *prometheus.GaugeVec
+	dnsRecordTTLMetric  *prometheus.GaugeVec
 
 	// Project metrics
 	projectAmountMetric    *prometheus.GaugeVec
@@ -40,6 +307,8 @@ type Exporter struct {
 	projectDiskLimitMetric *prometheus.GaugeVec
 	projectBwUsageMetric   *prometheus.GaugeVec
 	projectBwLimitMetric   *prometheus.GaugeVec
+	projectCountMetric     *prometheus.GaugeVec
+	servicesCountMetric    *prometheus.GaugeVec
 
 	// Server metrics
 	serverRAMMetric     *prometheus.GaugeVec
@@ -52,37 +321,108 @@ type Exporter struct {
 	invoiceAmountMetric   *prometheus.GaugeVec
 
 	// Cloud resources metrics
-	cloudQuotaMetric        *prometheus.GaugeVec
-	cloudSummaryMetric      *prometheus.GaugeVec
-	cloudInstanceInfoMetric *prometheus.GaugeVec
+	cloudQuotaMetric           *prometheus.GaugeVec
+	cloudSummaryMetric         *prometheus.GaugeVec
+	cloudInstanceInfoMetric    *prometheus.GaugeVec
+	cloudInstanceFieldsMetric  *prometheus.GaugeVec
+	cloudInstanceImageMetric   *prometheus.GaugeVec
+	cloudInstanceStatusMetric  *prometheus.GaugeVec
+	cloudTransitionMetric      *prometheus.GaugeVec
+	cloudInstanceCreatedMetric *prometheus.GaugeVec
+	cloudInstanceFixedIPMetric *prometheus.GaugeVec
+	cloudInstanceFloatIPMetric *prometheus.GaugeVec
+	floatingIPUtilMetric       *prometheus.GaugeVec
+	floatingIPQuotaUsedMetric  *prometheus.GaugeVec
+	floatingIPQuotaLimitMetric *prometheus.GaugeVec
 
 	// VPS metrics
-	vpsServerStatusMetric     *prometheus.GaugeVec
-	vpsServerRamMetric        *prometheus.GaugeVec
-	vpsServerCoresMetric      *prometheus.GaugeVec
-	vpsServerDiskMetric       *prometheus.GaugeVec
-	vpsServerBackupMetric     *prometheus.GaugeVec
-	vpsServerIpsProtectMetric *prometheus.GaugeVec
-	vpsServerAmountMetric     *prometheus.GaugeVec
+	vpsServerStatusMetric    *prometheus.GaugeVec
+	vpsServerRamMetric       *prometheus.GaugeVec
+	vpsServerCoresMetric     *prometheus.GaugeVec
+	vpsServerCreatedMetric   *prometheus.GaugeVec
+	vpsServerExpiryMetric    *prometheus.GaugeVec
+	vpsServerDiskMetric      *prometheus.GaugeVec
+	vpsServerBackupMetric    *prometheus.GaugeVec
+	vpsBackupsTotalMetric    *prometheus.GaugeVec
+	vpsServerAmountMetric    *prometheus.GaugeVec
+	vpsServerCountMetric     *prometheus.GaugeVec
+	vpsBackupStatusMetric    *prometheus.GaugeVec
+	vpsLatestBackupAgeMetric *prometheus.GaugeVec
+	vpsHasBackupMetric       *prometheus.GaugeVec
+	vpsServerImageMetric     *prometheus.GaugeVec
 
 	// K8S metrics
-	k8sClusterCountMetric    *prometheus.GaugeVec
-	k8sClusterStatusMetric   *prometheus.GaugeVec
-	k8sClusterNodesMetric    *prometheus.GaugeVec
-	k8sClusterMastersMetric  *prometheus.GaugeVec
-	k8sNodeGroupStatusMetric *prometheus.GaugeVec
-	k8sNodeGroupNodesMetric  *prometheus.GaugeVec
-	k8sNodeGroupCoresMetric  *prometheus.GaugeVec
-	k8sNodeGroupRAMMetric    *prometheus.GaugeVec
+	k8sClusterCountMetric        *prometheus.GaugeVec
+	k8sClusterStatusMetric       *prometheus.GaugeVec
+	k8sClusterNodesMetric        *prometheus.GaugeVec
+	k8sClusterMastersMetric      *prometheus.GaugeVec
+	k8sClusterAPIHealthyMetric   *prometheus.GaugeVec
+	k8sNodeGroupStatusMetric     *prometheus.GaugeVec
+	k8sNodeGroupNodesMetric      *prometheus.GaugeVec
+	k8sNodeGroupReadyNodesMetric *prometheus.GaugeVec
+	k8sNodeGroupCoresMetric      *prometheus.GaugeVec
+	k8sNodeGroupRAMMetric        *prometheus.GaugeVec
+	k8sNodeGroupDiskMetric       *prometheus.GaugeVec
+	k8sTemplateCoresMetric       *prometheus.GaugeVec
+	k8sTemplateRAMMetric         *prometheus.GaugeVec
+	k8sProjectQuotaLimitMetric   *prometheus.GaugeVec
+	k8sProjectQuotaUsedMetric    *prometheus.GaugeVec
+	k8sProjectStatusDesc         *prometheus.Desc
+	k8sProjectTypeDesc           *prometheus.Desc
 
 	// LBaaS metrics
 	lbaasLoadBalancerCountMetric  *prometheus.GaugeVec
 	lbaasLoadBalancerStatusMetric *prometheus.GaugeVec
 	lbaasListenersCountMetric     *prometheus.GaugeVec
+	lbaasListenersAccountMetric   *prometheus.GaugeVec
+	lbaasPoolsAccountMetric       *prometheus.GaugeVec
+	lbaasMembersAccountMetric     *prometheus.GaugeVec
 	lbaasPoolsCountMetric         *prometheus.GaugeVec
 	lbaasMembersCountMetric       *prometheus.GaugeVec
+	lbaasMemberHealthMetric       *prometheus.GaugeVec
+	lbaasUnhealthyMetric          *prometheus.GaugeVec
 	lbaasFlavorMetric             *prometheus.GaugeVec
 	lbaasFloatingIPMetric         *prometheus.GaugeVec
+	lbaasProvisioningAgeMetric    *prometheus.GaugeVec
+
+	// lbaasPending holds the load balancers in a pending provisioning state,
+	// their age is computed on every scrape from the time the operation started
+	lbaasPending []pendingLoadBalancer
+
+	// group deduplicates concurrent collections
+	group singleflight.Group
+
+	// scrapeWindow holds the outcomes of the most recent scrapes
+	scrapeWindow *scrapeWindow
+	// failingSince is when the current run of failed scrapes began, zero while
+	// scrapes succeed. It is guarded by healthMutex so readiness checks do not
+	// wait for a running scrape.
+	failingSince time.Time
+	healthMutex  sync.Mutex
+	// successPolicy decides the scrape outcome from the fetched data sources
+	successPolicy string
+
+	// debouncer holds back flapping values of selected metric families
+	debouncer *debouncer
+	// relabeler applies the configured relabel rules, nil if there are none
+	relabeler *relabeler
+	// cardinality limits the number of series per metric family
+	cardinality *cardinalityGuard
+	// dropZeroSeries omits zero-valued series of the families in zeroDropMetrics
+	dropZeroSeries bool
+	// only restricts scrapes to a single collector, empty for all
+	only string
+
+	// fetchErrors tracks which data sources failed during the current scrape
+	fetchErrors map[string]bool
+	// lastSuccess holds the time of the last successful fetch per collector name
+	lastSuccess map[string]time.Time
+	// previousPrepay holds the last fetched prepay balance per account label
+	previousPrepay map[string]float64
+	// lastScrape is the start time of the previous scrape, zero before the first one
+	lastScrape time.Time
+	// configLoadedAt is when the configuration in use was loaded, zero if unknown
+	configLoadedAt time.Time
 
 	mutex  *sync.Mutex
 	logger kitlog.Logger
@@ -90,9 +430,39 @@ type Exporter struct {
 
 // New creates a new Exporter instance
 func New(c *client.Client, serviceID string) *Exporter {
-	return &Exporter{
+	return NewWithOptions(c, Options{ServiceID: serviceID})
+}
+
+// NewWithOptions creates a new Exporter instance with custom options
+func NewWithOptions(c *client.Client, options Options) *Exporter {
+	e := &Exporter{
 		client:    c,
-		serviceID: serviceID,
+		serviceID: options.ServiceID,
+		dnsZones:  options.DNSZones,
+
+		projectLabel:        options.ProjectLabel,
+		domainStatuses:      options.DomainStatuses,
+		domainExpiryBuckets: expiryBuckets(options.DomainExpiryBuckets),
+		vpsServers:          options.VPSServers,
+		cloudInstances:      options.CloudInstances,
+		cloudInstanceFields: options.CloudInstanceFields,
+
+		regionNames: options.RegionNames,
+		regions:     make(map[string]string),
+
+		deltaExposition:   options.DeltaExposition,
+		dropZeroSeries:    options.DropZeroSeries,
+		metricTimestamps:  options.MetricTimestamps,
+		groupFetched:      make(map[string]time.Time),
+		only:              options.Only,
+		configLoadedAt:    options.ConfigLoadedAt,
+		groupFingerprints: make(map[string]uint64),
+		groupRegions:      make(map[string]map[string]string),
+
+		collectAccountVerification: options.AccountVerification,
+		collectBankCards:           options.BankCards,
+		collectDomainPrices:        options.DomainPrices,
+		collectDomainAutoRenew:     options.DomainAutoRenew,
 
 		// Scrape metrics
 		scrapeDurationMetric: prometheus.NewGauge(
@@ -102,6 +472,20 @@ func New(c *client.Client, serviceID string) *Exporter {
 				Help:      "Duration of the last scrape in seconds",
 			},
 		),
+		mutexWaitMetric: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "scrape_mutex_wait_seconds",
+				Help:      "Time the last collection waited for the collector lock, held by status page requests, in seconds",
+			},
+		),
+		scrapeIntervalMetric: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "scrape_interval_seconds",
+				Help:      "Observed time between the starts of the last two scrapes in seconds",
+			},
+		),
 		scrapeSuccessMetric: prometheus.NewGauge(
 			prometheus.GaugeOpts{
 				Namespace: "pskz",
@@ -109,6 +493,34 @@ func New(c *client.Client, serviceID string) *Exporter {
 				Help:      "Whether the last scrape was successful (1 for success, 0 for failure)",
 			},
 		),
+		scrapeErrorRatioMetric: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "scrape_error_ratio",
+				Help:      "Share of failed scrapes among the most recent scrapes (see scrapeErrorWindow)",
+			},
+		),
+		scrapeSharedMetric: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: "pskz",
+				Name:      "scrape_shared_total",
+				Help:      "Total number of scrapes that received the results of a collection already in progress",
+			},
+		),
+		scrapeAttemptsMetric: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: "pskz",
+				Name:      "scrape_attempts_total",
+				Help:      "Total number of scrapes attempted",
+			},
+		),
+		scrapeSuccessesMetric: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: "pskz",
+				Name:      "scrape_successes_total",
+				Help:      "Total number of successful scrapes",
+			},
+		),
 		lastScrapeErrorMetric: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace: "pskz",
@@ -117,6 +529,130 @@ func New(c *client.Client, serviceID string) *Exporter {
 			},
 			[]string{"error_type"},
 		),
+		monitoredErrorMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "monitored_resource_fetch_error",
+				Help:      "Whether the last fetch of a monitored VPS server or cloud instance failed (1 = error)",
+			},
+			[]string{"kind", "id"},
+		),
+		schemaMissingMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "schema_field_missing",
+				Help:      "Expected field absent from an API response in the last scrape (always 1)",
+			},
+			[]string{"endpoint", "field"},
+		),
+		scrapeErrorsMetric: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "scrape_errors_count",
+				Help:      "Number of data sources that failed during the last scrape",
+			},
+		),
+		dataAgeMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "collector_data_age_seconds",
+				Help:      "Seconds since the data source of the collector was last fetched successfully",
+			},
+			[]string{"collector"},
+		),
+		rateLimitRemainingMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "api_ratelimit_remaining",
+				Help:      "Remaining API requests in the current rate limit window, as reported by PS.KZ",
+			},
+			[]string{"endpoint"},
+		),
+		circuitOpenMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "circuit_open",
+				Help:      "Whether calls to the API endpoint are skipped after repeated failures (1 = open)",
+			},
+			[]string{"endpoint"},
+		),
+		rateLimitResetMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "api_ratelimit_reset_timestamp_seconds",
+				Help:      "Unix time when the API rate limit window resets, as reported by PS.KZ",
+			},
+			[]string{"endpoint"},
+		),
+		reauthDesc: prometheus.NewDesc(
+			"pskz_api_reauth_total",
+			"Number of re-authentications performed after the API rejected the token",
+			nil,
+			nil,
+		),
+		regionInfoMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "region_info",
+				Help:      "Display name of a region in use (always 1)",
+			},
+			[]string{"region_id", "region_name"},
+		),
+		regionsInUseMetric: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "regions_in_use_count",
+				Help:      "Number of distinct regions the account's resources are in",
+			},
+		),
+		exporterInfoMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "exporter_info",
+				Help:      "Identifying configuration of this exporter instance (always 1)",
+			},
+			[]string{"version", "build", "base_url", "service_id"},
+		),
+		configAgeMetric: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "config_age_seconds",
+				Help:      "Seconds since the configuration in use was loaded",
+			},
+		),
+		tokenSourceMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "token_source_info",
+				Help:      "Where the API token in use was configured: flag, env, token_file or config (always 1)",
+			},
+			[]string{"source"},
+		),
+		cacheServedMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "scrape_cache_served_count",
+				Help:      "Number of data groups in the last scrape that were kept from a previous scrape (cached) or recomputed (fresh)",
+			},
+			[]string{"source"},
+		),
+		responseBytesMetric: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: "pskz",
+				Name:      "api_response_bytes",
+				Help:      "Size of PS.KZ API response bodies in bytes",
+				Buckets:   prometheus.ExponentialBuckets(1024, 4, 8),
+			},
+			[]string{"endpoint"},
+		),
+		targetInfoMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "target_info",
+				Help:      "Information about the PS.KZ API backend this exporter scrapes (always 1)",
+			},
+			[]string{"base_url"},
+		),
 
 		// Balance metrics
 		prepayMetric: prometheus.NewGaugeVec(
@@ -127,6 +663,14 @@ func New(c *client.Client, serviceID string) *Exporter {
 			},
 			[]string{"account"},
 		),
+		prepayChangeMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "prepay_balance_change",
+				Help:      "Change of the prepay balance since the previous scrape that fetched it (0 on the first)",
+			},
+			[]string{"account"},
+		),
 		creditMetric: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace: "pskz",
@@ -159,6 +703,72 @@ func New(c *client.Client, serviceID string) *Exporter {
 			},
 			[]string{"account"},
 		),
+		creditOverdueMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "credit_overdue",
+				Help:      "Whether used credit is past its repayment date (1 = overdue)",
+			},
+			[]string{"account"},
+		),
+		accountCoresMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "account_cpu_cores_count",
+				Help:      "Total CPU cores of cloud instances, VPS servers and Kubernetes nodes",
+			},
+			[]string{"account"},
+		),
+		accountRAMMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "account_ram_gb",
+				Help:      "Total RAM of cloud instances, VPS servers and Kubernetes nodes in GB",
+			},
+			[]string{"account"},
+		),
+		accountInstancesMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "account_instances_count",
+				Help:      "Total number of cloud instances, VPS servers and Kubernetes nodes",
+			},
+			[]string{"account"},
+		),
+		accountPlanMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "account_plan_info",
+				Help:      "Plan of the account (always 1)",
+			},
+			[]string{"plan"},
+		),
+
+		// Account metrics
+		verificationExpiryMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "account_verification_expiry_timestamp_seconds",
+				Help:      "Unix time when the account verification expires",
+			},
+			[]string{},
+		),
+		bankCardsMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "account_bank_cards_count",
+				Help:      "Number of payment cards attached to the account",
+			},
+			[]string{},
+		),
+		cardExpiryMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "account_card_expiry_timestamp_seconds",
+				Help:      "Unix time when a payment card expires, when reported by the API",
+			},
+			[]string{"card"},
+		),
 
 		// Domain metrics
 		domainExpiryMetric: prometheus.NewGaugeVec(
@@ -185,6 +795,105 @@ func New(c *client.Client, serviceID string) *Exporter {
 			},
 			[]string{"domain"},
 		),
+		domainDNSSECMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "domain_dnssec_enabled",
+				Help:      "Whether DNSSEC is enabled for the domain (1 = enabled, 0 = disabled)",
+			},
+			[]string{"domain"},
+		),
+		domainNSCountMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "domain_nameserver_count",
+				Help:      "Number of nameservers delegated for the domain",
+			},
+			[]string{"domain"},
+		),
+
+		domainAutoRenewMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "domain_autorenew_enabled",
+				Help:      "Whether auto-renew is enabled for the domain (1 = enabled, 0 = disabled)",
+			},
+			[]string{"domain"},
+		),
+		domainLastUpdateMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "domain_last_update_timestamp_seconds",
+				Help:      "Unix time of the last whois update of the domain",
+			},
+			[]string{"domain"},
+		),
+		domainsExpiringMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "domains_expiring_within_count",
+				Help:      "Number of domains expiring within the bucket's number of days, excluding expired domains",
+			},
+			[]string{"bucket"},
+		),
+		domainLastTransferMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "domain_last_transfer_timestamp_seconds",
+				Help:      "Unix time of the last transfer of the domain",
+			},
+			[]string{"domain"},
+		),
+		domainPrivacyMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "domain_privacy_enabled",
+				Help:      "Whether whois privacy protection is enabled for the domain contact (1 = yes, 0 = no)",
+			},
+			[]string{"domain", "contact"},
+		),
+		domainContactMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "domain_contact_updated_timestamp_seconds",
+				Help:      "Unix time of the last whois update of the domain contact",
+			},
+			[]string{"domain", "contact"},
+		),
+		domainPriceMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "domain_price",
+				Help:      "Domain price per zone and operation",
+			},
+			[]string{"zone", "operation"},
+		),
+		domainPriceDiffMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "domain_zone_price_reg_renew_diff",
+				Help:      "Registration price minus renewal price per domain zone",
+			},
+			[]string{"zone"},
+		),
+
+		// DNS metrics
+		dnsRecordInfoMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "dns_record_info",
+				Help:      "DNS record in a managed zone (always 1)",
+			},
+			[]string{"zone", "type", "name", "value"},
+		),
+		dnsRecordTTLMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "dns_record_ttl_seconds",
+				Help:      "TTL of a DNS record in a managed zone in seconds",
+			},
+			[]string{"zone", "type", "name", "value"},
+		),
 
 		// Project metrics
 		projectAmountMetric: prometheus.NewGaugeVec(
@@ -193,7 +902,7 @@ func New(c *client.Client, serviceID string) *Exporter {
 				Name:      "project_amount",
 				Help:      "Project amount",
 			},
-			[]string{"project"},
+			[]string{"project", "project_id"},
 		),
 		projectDiskUsageMetric: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
@@ -201,7 +910,7 @@ func New(c *client.Client, serviceID string) *Exporter {
 				Name:      "project_disk_usage_gb",
 				Help:      "Project disk usage in GB",
 			},
-			[]string{"project"},
+			[]string{"project", "project_id"},
 		),
 		projectDiskLimitMetric: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
@@ -209,7 +918,7 @@ func New(c *client.Client, serviceID string) *Exporter {
 				Name:      "project_disk_limit_gb",
 				Help:      "Project disk limit in GB",
 			},
-			[]string{"project"},
+			[]string{"project", "project_id"},
 		),
 		projectBwUsageMetric: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
@@ -217,7 +926,7 @@ func New(c *client.Client, serviceID string) *Exporter {
 				Name:      "project_bw_usage_gb",
 				Help:      "Project bandwidth usage in GB",
 			},
-			[]string{"project"},
+			[]string{"project", "project_id"},
 		),
 		projectBwLimitMetric: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
@@ -225,7 +934,23 @@ func New(c *client.Client, serviceID string) *Exporter {
 				Name:      "project_bw_limit_gb",
 				Help:      "Project bandwidth limit in GB",
 			},
-			[]string{"project"},
+			[]string{"project", "project_id"},
+		),
+		projectCountMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "project_count",
+				Help:      "Number of projects by status",
+			},
+			[]string{"status"},
+		),
+		servicesCountMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "account_services_count",
+				Help:      "Number of account services by type",
+			},
+			[]string{"type"},
 		),
 
 		// Server metrics
@@ -287,7 +1012,7 @@ func New(c *client.Client, serviceID string) *Exporter {
 				Name:      "cloud_quota",
 				Help:      "Cloud quota",
 			},
-			[]string{"resource"},
+			[]string{"resource", "region_id"},
 		),
 		cloudSummaryMetric: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
@@ -305,69 +1030,205 @@ func New(c *client.Client, serviceID string) *Exporter {
 			},
 			[]string{"resource", "info"},
 		),
-
-		// VPS metrics
-		vpsServerStatusMetric: prometheus.NewGaugeVec(
+		cloudInstanceImageMetric: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace: "pskz",
-				Name:      "vps_server_status",
-				Help:      "VPS server status (1 = active, 0 = inactive)",
+				Name:      "cloud_instance_image_info",
+				Help:      "OS image of the cloud instance (always 1)",
 			},
-			[]string{"instance_name", "status"},
+			[]string{"instance_name", "image"},
 		),
-		vpsServerRamMetric: prometheus.NewGaugeVec(
+		cloudInstanceStatusMetric: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace: "pskz",
-				Name:      "vps_server_ram_mb",
-				Help:      "VPS server RAM in MB",
+				Name:      "cloud_instance_status",
+				Help:      "Status of the cloud instance as reported by the API (always 1)",
 			},
-			[]string{"instance_name"},
+			[]string{"instance_name", "status"},
 		),
-		vpsServerCoresMetric: prometheus.NewGaugeVec(
+		cloudTransitionMetric: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace: "pskz",
-				Name:      "vps_server_cores",
-				Help:      "VPS server CPU cores",
+				Name:      "cloud_instances_in_transition_count",
+				Help:      "Number of cloud instances in a transitional state such as BUILD or RESIZE",
 			},
-			[]string{"instance_name"},
+			[]string{"status"},
 		),
-		vpsServerDiskMetric: prometheus.NewGaugeVec(
+		cloudInstanceFieldsMetric: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace: "pskz",
-				Name:      "vps_server_disk_gb",
-				Help:      "VPS server disk usage in GB",
+				Name:      "cloud_instance_fields_info",
+				Help:      "Additional cloud instance fields selected with cloudInstanceFields (always 1)",
 			},
-			[]string{"instance_name"},
+			append([]string{"instance_name"}, fieldLabelNames(options.CloudInstanceFields)...),
 		),
-		vpsServerBackupMetric: prometheus.NewGaugeVec(
+		cloudInstanceCreatedMetric: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace: "pskz",
-				Name:      "vps_server_backup_gb",
-				Help:      "VPS server backup usage in GB",
+				Name:      "cloud_instance_created_timestamp_seconds",
+				Help:      "Unix time when the cloud instance was created",
 			},
 			[]string{"instance_name"},
 		),
-		vpsServerIpsProtectMetric: prometheus.NewGaugeVec(
+		cloudInstanceFixedIPMetric: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace: "pskz",
-				Name:      "vps_server_ips_protect",
-				Help:      "VPS server IPs protect",
+				Name:      "cloud_instance_fixed_ip_count",
+				Help:      "Number of fixed IP addresses of the cloud instance by IP version",
 			},
-			[]string{"instance_name"},
+			[]string{"instance_name", "ip_version"},
 		),
-		vpsServerAmountMetric: prometheus.NewGaugeVec(
+		cloudInstanceFloatIPMetric: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace: "pskz",
-				Name:      "vps_server_amount",
-				Help:      "VPS server amount",
+				Name:      "cloud_instance_floating_ip_count",
+				Help:      "Number of floating IP addresses of the cloud instance by IP version",
 			},
-			[]string{"instance_name"},
+			[]string{"instance_name", "ip_version"},
 		),
-
-		// K8S metrics
-		k8sClusterCountMetric: prometheus.NewGaugeVec(
+		floatingIPUtilMetric: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
-				Name: "pskz_k8s_cluster_count",
+				Namespace: "pskz",
+				Name:      "floating_ip_utilization_ratio",
+				Help:      "Ratio of floating IPs in use to the floating IP quota",
+			},
+			[]string{"region_id"},
+		),
+		floatingIPQuotaUsedMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "floating_ip_quota_used_count",
+				Help:      "Number of floating IPs counted against the floating IP quota",
+			},
+			[]string{"region_id"},
+		),
+		floatingIPQuotaLimitMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "floating_ip_quota_limit_count",
+				Help:      "Floating IP quota",
+			},
+			[]string{"region_id"},
+		),
+
+		// VPS metrics
+		vpsServerStatusMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "vps_server_status",
+				Help:      "VPS server status (1 = active, 0 = inactive)",
+			},
+			[]string{"server_id", "instance_name", "status"},
+		),
+		vpsServerRamMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "vps_server_ram_mb",
+				Help:      "VPS server RAM in MB",
+			},
+			[]string{"server_id", "instance_name", "region_id"},
+		),
+		vpsServerCoresMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "vps_server_cores",
+				Help:      "VPS server CPU cores",
+			},
+			[]string{"server_id", "instance_name", "region_id"},
+		),
+		vpsServerCreatedMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "vps_server_created_timestamp_seconds",
+				Help:      "VPS server creation time as a Unix timestamp",
+			},
+			[]string{"server_id", "instance_name"},
+		),
+		vpsServerExpiryMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "vps_server_expiry_timestamp_seconds",
+				Help:      "Time the VPS server is paid until as a Unix timestamp",
+			},
+			[]string{"server_id", "instance_name"},
+		),
+		vpsServerDiskMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "vps_server_disk_gb",
+				Help:      "VPS server disk usage in GB",
+			},
+			[]string{"instance_name"},
+		),
+		vpsServerBackupMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "vps_server_backup_gb",
+				Help:      "VPS server backup usage in GB",
+			},
+			[]string{"instance_name"},
+		),
+		vpsBackupsTotalMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "vps_backups_total_size_gb",
+				Help:      "Total size of the backups of all VPS servers in GB",
+			},
+			[]string{"account"},
+		),
+		vpsServerAmountMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "vps_server_amount",
+				Help:      "VPS server amount",
+			},
+			[]string{"instance_name"},
+		),
+		vpsServerCountMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "vps_server_count",
+				Help:      "Number of VPS servers by region and status",
+			},
+			[]string{"region_id", "status"},
+		),
+		vpsBackupStatusMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "vps_backup_status",
+				Help:      "VPS backup status (1 = completed, 0 = failed or in progress)",
+			},
+			[]string{"server_id", "instance_name", "backup_name", "status"},
+		),
+		vpsLatestBackupAgeMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "vps_latest_backup_age_seconds",
+				Help:      "Age of the newest backup of the VPS server in seconds",
+			},
+			[]string{"server_id", "instance_name"},
+		),
+		vpsServerImageMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "vps_server_image_info",
+				Help:      "OS image of the VPS server (always 1)",
+			},
+			[]string{"server_id", "image"},
+		),
+		vpsHasBackupMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "vps_has_backup",
+				Help:      "Whether the VPS server has at least one backup (1 = yes, 0 = no)",
+			},
+			[]string{"server_id", "instance_name"},
+		),
+
+		// K8S metrics
+		k8sClusterCountMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "pskz_k8s_cluster_count",
 				Help: "Number of Kubernetes clusters",
 			},
 			[]string{"status"},
@@ -393,6 +1254,13 @@ func New(c *client.Client, serviceID string) *Exporter {
 			},
 			[]string{"cluster_id", "name"},
 		),
+		k8sClusterAPIHealthyMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "pskz_k8s_cluster_api_healthy",
+				Help: "Whether the Kubernetes cluster API server is reachable (1=healthy, 0=unhealthy)",
+			},
+			[]string{"cluster_id", "name"},
+		),
 		k8sNodeGroupStatusMetric: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Name: "pskz_k8s_nodegroup_status",
@@ -407,6 +1275,13 @@ func New(c *client.Client, serviceID string) *Exporter {
 			},
 			[]string{"cluster_id", "cluster_name", "nodegroup_id", "nodegroup_name"},
 		),
+		k8sNodeGroupReadyNodesMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "pskz_k8s_nodegroup_ready_nodes_count",
+				Help: "Number of ready nodes in Kubernetes node group, when reported by the API",
+			},
+			[]string{"cluster_id", "cluster_name", "nodegroup_id", "nodegroup_name"},
+		),
 		k8sNodeGroupCoresMetric: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Name: "pskz_k8s_nodegroup_cores",
@@ -421,6 +1296,53 @@ func New(c *client.Client, serviceID string) *Exporter {
 			},
 			[]string{"cluster_id", "cluster_name", "nodegroup_id", "nodegroup_name"},
 		),
+		k8sNodeGroupDiskMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "pskz_k8s_nodegroup_disk_gb",
+				Help: "Root disk size per node in Kubernetes node group (GB)",
+			},
+			[]string{"cluster_id", "cluster_name", "nodegroup_id", "nodegroup_name"},
+		),
+		k8sTemplateCoresMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "pskz_k8s_template_total_cores_count",
+				Help: "Total CPU cores of the node groups of Kubernetes clusters by cluster template",
+			},
+			[]string{"template_name"},
+		),
+		k8sTemplateRAMMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "pskz_k8s_template_total_ram_mb",
+				Help: "Total RAM of the node groups of Kubernetes clusters by cluster template (MB)",
+			},
+			[]string{"template_name"},
+		),
+		k8sProjectQuotaLimitMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "pskz_k8s_project_quota_limit",
+				Help: "Quota limit of a Kubernetes project by OpenStack service and quota key",
+			},
+			[]string{"project_id", "project_name", "region_id", "service", "key"},
+		),
+		k8sProjectQuotaUsedMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "pskz_k8s_project_quota_used",
+				Help: "Quota usage of a Kubernetes project by OpenStack service and quota key",
+			},
+			[]string{"project_id", "project_name", "region_id", "service", "key"},
+		),
+		k8sProjectStatusDesc: prometheus.NewDesc(
+			"pskz_k8s_project_status_count",
+			"Number of Kubernetes projects by status",
+			[]string{"status"},
+			nil,
+		),
+		k8sProjectTypeDesc: prometheus.NewDesc(
+			"pskz_k8s_project_type_count",
+			"Number of Kubernetes projects by type",
+			[]string{"type"},
+			nil,
+		),
 
 		// LBaaS metrics
 		lbaasLoadBalancerCountMetric: prometheus.NewGaugeVec(
@@ -463,6 +1385,46 @@ func New(c *client.Client, serviceID string) *Exporter {
 			},
 			[]string{"loadbalancer_id", "loadbalancer_name"},
 		),
+		lbaasListenersAccountMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "lbaas_account_listeners_count",
+				Help:      "Count of LBaaS listeners across all load balancers",
+			},
+			[]string{},
+		),
+		lbaasPoolsAccountMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "lbaas_account_pools_count",
+				Help:      "Count of LBaaS pools across all load balancers",
+			},
+			[]string{},
+		),
+		lbaasMembersAccountMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "lbaas_account_members_count",
+				Help:      "Count of LBaaS members across all load balancers",
+			},
+			[]string{},
+		),
+		lbaasMemberHealthMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "lbaas_member_health",
+				Help:      "Count of LBaaS members per load balancer by operating status",
+			},
+			[]string{"loadbalancer_id", "loadbalancer_name", "state"},
+		),
+		lbaasUnhealthyMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "lbaas_unhealthy",
+				Help:      "Whether an ACTIVE LBaaS load balancer has no ONLINE members (1 = unhealthy, 0 = healthy or unknown)",
+			},
+			[]string{"loadbalancer_id", "loadbalancer_name"},
+		),
 		lbaasFlavorMetric: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace: "pskz",
@@ -479,30 +1441,385 @@ func New(c *client.Client, serviceID string) *Exporter {
 			},
 			[]string{"loadbalancer_id", "loadbalancer_name"},
 		),
+		lbaasProvisioningAgeMetric: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pskz",
+				Name:      "lbaas_provisioning_age_seconds",
+				Help:      "Seconds a load balancer has been in a pending provisioning state",
+			},
+			[]string{"loadbalancer_id", "loadbalancer_name", "status"},
+		),
+
+		scrapeWindow:  newScrapeWindow(options.ScrapeErrorWindow),
+		successPolicy: options.SuccessPolicy,
+
+		debouncer:   newDebouncer(options.Debounce),
+		relabeler:   newRelabeler(options.Relabel),
+		cardinality: newCardinalityGuard(options.MaxSeriesPerMetric),
+		fetchErrors: make(map[string]bool),
+		lastSuccess: make(map[string]time.Time),
+
+		previousPrepay: make(map[string]float64),
 
 		mutex:  &sync.Mutex{},
-		logger: kitlog.NewLogfmtLogger(kitlog.NewSyncWriter(log.Writer())),
+		logger: level.NewFilter(kitlog.NewLogfmtLogger(kitlog.NewSyncWriter(log.Writer())), level.AllowInfo()),
+	}
+
+	// The token is deliberately not part of the exporter identity
+	e.exporterInfoMetric.WithLabelValues(sanitizeLabelValues(options.Version, options.Build, c.BaseURL(), options.ServiceID)...).Set(1)
+	if options.TokenSource != "" {
+		e.tokenSourceMetric.WithLabelValues(sanitizeLabelValue(options.TokenSource)).Set(1)
+	}
+
+	e.groupMetrics = e.deltaGroups()
+	e.groupDescs = groupDescs(e.groupMetrics)
+
+	return e
+}
+
+// enabled reports whether the named collector runs in this scrape. configured is
+// its own setting, which is overridden when scrapes are restricted to one collector.
+// Collectors of disabled services never run.
+func (e *Exporter) enabled(name string, configured bool) bool {
+	if service, ok := collectorServices[name]; ok && !e.client.ServiceEnabled(service) {
+		return false
+	}
+	if e.only != "" {
+		return name == e.only
+	}
+	return configured
+}
+
+// recordFetchError records that fetching the given data source failed with err.
+// Non-JSON responses, such as maintenance pages, are also recorded as html_response.
+func (e *Exporter) recordFetchError(errorType string, err error) {
+	e.setFetchError(errorType, true)
+	if errors.Is(err, client.ErrNonJSONResponse) {
+		e.setFetchError(htmlResponseError, true)
+	}
+}
+
+// fetchErrorCollectors maps data sources to the collector in CollectorNames they
+// belong to. A collector's data counts as fresh when any of its sources was fetched.
+// Domain counters are left out, as they are stubbed in the client and never fetched.
+var fetchErrorCollectors = map[string]string{
+	"account_verification_fetch_error": "account_verification",
+	"balance_fetch_error":              "balance",
+	"bank_cards_fetch_error":           "bank_cards",
+	"cloud_instances_fetch_error":      "cloud_instances",
+	"cloud_resources_fetch_error":      "cloud_resources",
+	"dns_records_fetch_error":          "dns_records",
+	"domain_prices_fetch_error":        "domain_prices",
+	"domains_fetch_error":              "domains",
+	"extended_balance_fetch_error":     "balance",
+	"invoices_fetch_error":             "invoices",
+	"k8s_clusters_fetch_error":         "k8s_clusters",
+	"k8s_projects_fetch_error":         "k8s_projects",
+	"lbaas_loadbalancers_fetch_error":  "lbaas",
+	"projects_fetch_error":             "projects",
+	"service_vps_servers_fetch_error":  "cloud_servers",
+	"vpc_servers_fetch_error":          "cloud_servers",
+	"vps_backups_fetch_error":          "vps_servers",
+	"vps_servers_fetch_error":          "vps_servers",
+}
+
+// setFetchError records whether fetching the given data source failed
+// and updates the per-source error metric and the aggregated error count
+func (e *Exporter) setFetchError(errorType string, failed bool) {
+	e.fetchErrors[errorType] = failed
+	if collector, ok := fetchErrorCollectors[errorType]; ok && !failed {
+		e.lastSuccess[collector] = time.Now()
+	}
+
+	value := 0.0
+	if failed {
+		value = 1
+	}
+	e.lastScrapeErrorMetric.WithLabelValues(errorType).Set(value)
+
+	count := 0
+	for _, failed := range e.fetchErrors {
+		if failed {
+			count++
+		}
+	}
+	e.scrapeErrorsMetric.Set(float64(count))
+}
+
+// markSchemaFieldMissing flags a field that is absent from the response of endpoint.
+// The field is given as its dotted path from the response root.
+func (e *Exporter) markSchemaFieldMissing(endpoint, field string) {
+	e.schemaMissingMetric.WithLabelValues(endpoint, field).Set(1)
+}
+
+// paginationOf returns the pagination object of a paginated API result.
+// A null or absent pagination is a legitimate empty result and yields an empty object;
+// ok is false only if the pagination is malformed.
+func (e *Exporter) paginationOf(parent map[string]interface{}, source string) (map[string]interface{}, bool) {
+	raw, present := parent["pagination"]
+	if !present || raw == nil {
+		level.Debug(e.logger).Log("msg", "Pagination is null or absent, treating as empty", "source", source)
+		return map[string]interface{}{}, true
+	}
+
+	pagination, ok := raw.(map[string]interface{})
+	if !ok {
+		level.Warn(e.logger).Log("msg", "Invalid data structure: pagination is not an object", "source", source)
+		return nil, false
+	}
+	return pagination, true
+}
+
+// paginationItems returns the items of a pagination object.
+// Null or absent items are a legitimate empty result; ok is false only if the items are malformed.
+func (e *Exporter) paginationItems(pagination map[string]interface{}, source string) ([]interface{}, bool) {
+	raw, present := pagination["items"]
+	if !present || raw == nil {
+		level.Debug(e.logger).Log("msg", "Items are null or absent, treating as empty", "source", source)
+		return nil, true
+	}
+
+	items, ok := raw.([]interface{})
+	if !ok {
+		level.Warn(e.logger).Log("msg", "Invalid data structure: items is not an array", "source", source)
+		return nil, false
+	}
+	return items, true
+}
+
+// scrapeSucceeded reports whether the scrape succeeded under the success policy.
+// By default a scrape fails only if no data source could be fetched at all.
+// Sources stubbed in the client do not count as fetched.
+func (e *Exporter) scrapeSucceeded() bool {
+	failed, fetched := false, false
+	for errorType, f := range e.fetchErrors {
+		if f {
+			failed = true
+		} else if _, ok := fetchErrorCollectors[errorType]; ok {
+			fetched = true
+		}
+	}
+
+	if e.successPolicy == SuccessPolicyAll {
+		return !failed
+	}
+	return fetched
+}
+
+// FailingSince returns when the current run of failed scrapes began, or the
+// zero time if the last scrape succeeded or none has completed yet
+func (e *Exporter) FailingSince() time.Time {
+	e.healthMutex.Lock()
+	defer e.healthMutex.Unlock()
+	return e.failingSince
+}
+
+// recordScrapeOutcome sets the scrape success metric and updates the error ratio over the recent scrapes
+func (e *Exporter) recordScrapeOutcome(success bool) {
+	value := 0.0
+	if success {
+		value = 1
+	}
+	e.scrapeSuccessMetric.Set(value)
+
+	e.healthMutex.Lock()
+	if success {
+		e.failingSince = time.Time{}
+	} else if e.failingSince.IsZero() {
+		e.failingSince = time.Now()
+	}
+	e.healthMutex.Unlock()
+
+	e.scrapeWindow.record(!success)
+	e.scrapeErrorRatioMetric.Set(e.scrapeWindow.errorRatio())
+}
+
+// collectCircuitStates exposes whether the circuit breaker of each API endpoint is open
+func (e *Exporter) collectCircuitStates(ch chan<- prometheus.Metric) {
+	e.circuitOpenMetric.Reset()
+	for endpoint, open := range e.client.CircuitStates() {
+		value := 0.0
+		if open {
+			value = 1
+		}
+		e.circuitOpenMetric.WithLabelValues(endpoint).Set(value)
+	}
+	e.circuitOpenMetric.Collect(ch)
+}
+
+// setMonitoredError sets the fetch error state of a monitored resource
+func (e *Exporter) setMonitoredError(kind, id string, failed bool) {
+	value := 0.0
+	if failed {
+		value = 1
+	}
+	e.monitoredErrorMetric.WithLabelValues(kind, sanitizeLabelValue(id)).Set(value)
+}
+
+// fetchVPSServers fetches the monitored VPS servers one by one and returns them
+// in the shape of a GetVpsServersStatus response. Servers that cannot be fetched
+// are left out and reported in pskz_monitored_resource_fetch_error; an error is
+// returned only if none of them could be fetched.
+func (e *Exporter) fetchVPSServers() (map[string]interface{}, error) {
+	items := make([]interface{}, 0, len(e.vpsServers))
+	var lastErr error
+	for _, server := range e.vpsServers {
+		id := strconv.Itoa(server.ServerID)
+		item, err := e.client.GetVPSServer(server.ServerID, server.RegionID)
+		if err != nil {
+			log.Printf("Error getting VPS server %d: %v", server.ServerID, err)
+			e.setMonitoredError("vps_server", id, true)
+			lastErr = err
+			continue
+		}
+		e.setMonitoredError("vps_server", id, false)
+		items = append(items, item)
+	}
+	if len(items) == 0 && lastErr != nil {
+		return nil, fmt.Errorf("failed to get all %d monitored VPS servers: %w", len(e.vpsServers), lastErr)
+	}
+
+	return map[string]interface{}{
+		"data": map[string]interface{}{
+			"vps": map[string]interface{}{
+				"server": map[string]interface{}{
+					"pagination": map[string]interface{}{
+						"items": items,
+						"count": float64(len(items)),
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// fetchCloudInstances fetches the monitored cloud instances one by one and returns
+// them in the shape of a GetCloudInstances response. Instances that cannot be
+// fetched are left out and reported in pskz_monitored_resource_fetch_error; an
+// error is returned only if none of them could be fetched.
+func (e *Exporter) fetchCloudInstances() (map[string]interface{}, error) {
+	items := make([]interface{}, 0, len(e.cloudInstances))
+	var lastErr error
+	for _, id := range e.cloudInstances {
+		item, err := e.client.GetCloudInstance(id, e.cloudInstanceFields)
+		if err != nil {
+			log.Printf("Error getting cloud instance %s: %v", id, err)
+			e.setMonitoredError("cloud_instance", id, true)
+			lastErr = err
+			continue
+		}
+		e.setMonitoredError("cloud_instance", id, false)
+		items = append(items, item)
+	}
+	if len(items) == 0 && lastErr != nil {
+		return nil, fmt.Errorf("failed to get all %d monitored cloud instances: %w", len(e.cloudInstances), lastErr)
+	}
+
+	return map[string]interface{}{
+		"data": map[string]interface{}{
+			"vpc": map[string]interface{}{
+				"instance": map[string]interface{}{
+					"pagination": map[string]interface{}{
+						"items": items,
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// observeRegion records a region seen in the API data together with its display name.
+// The name is taken from the API if present, then from the configured names, then the ID itself.
+func (e *Exporter) observeRegion(regionID string, apiName interface{}) {
+	if regionID == "" || regionID == "unknown" {
+		return
+	}
+
+	name, _ := apiName.(string)
+	if name == "" {
+		name = e.regionNames[regionID]
+	}
+	if name == "" {
+		if _, seen := e.regions[regionID]; seen {
+			return
+		}
+		name = regionID
+	}
+	e.regions[regionID] = name
+}
+
+// updateDataAge sets the data age metric for every collector that has fetched successfully
+func (e *Exporter) updateDataAge() {
+	e.dataAgeMetric.Reset()
+	for collector, last := range e.lastSuccess {
+		e.dataAgeMetric.WithLabelValues(collector).Set(time.Since(last).Seconds())
 	}
 }
 
 // Describe implements prometheus.Collector
 func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
 	e.scrapeDurationMetric.Describe(ch)
+	e.mutexWaitMetric.Describe(ch)
+	e.scrapeIntervalMetric.Describe(ch)
 	e.scrapeSuccessMetric.Describe(ch)
+	e.scrapeErrorRatioMetric.Describe(ch)
+	e.scrapeAttemptsMetric.Describe(ch)
+	e.scrapeSharedMetric.Describe(ch)
+	e.scrapeSuccessesMetric.Describe(ch)
 	e.lastScrapeErrorMetric.Describe(ch)
+	e.monitoredErrorMetric.Describe(ch)
+	e.schemaMissingMetric.Describe(ch)
+	e.scrapeErrorsMetric.Describe(ch)
+	e.dataAgeMetric.Describe(ch)
+	e.cardinality.hits.Describe(ch)
+	e.targetInfoMetric.Describe(ch)
+	e.responseBytesMetric.Describe(ch)
+	e.cacheServedMetric.Describe(ch)
+	e.exporterInfoMetric.Describe(ch)
+	e.tokenSourceMetric.Describe(ch)
+	e.configAgeMetric.Describe(ch)
+	e.regionInfoMetric.Describe(ch)
+	e.regionsInUseMetric.Describe(ch)
+	e.rateLimitRemainingMetric.Describe(ch)
+	e.rateLimitResetMetric.Describe(ch)
+	e.circuitOpenMetric.Describe(ch)
+	ch <- e.reauthDesc
 	e.prepayMetric.Describe(ch)
+	e.prepayChangeMetric.Describe(ch)
 	e.creditMetric.Describe(ch)
 	e.debtMetric.Describe(ch)
 	e.bonusMetric.Describe(ch)
 	e.blockedMetric.Describe(ch)
+	e.creditOverdueMetric.Describe(ch)
+	e.accountPlanMetric.Describe(ch)
+	e.accountCoresMetric.Describe(ch)
+	e.accountRAMMetric.Describe(ch)
+	e.accountInstancesMetric.Describe(ch)
+	e.verificationExpiryMetric.Describe(ch)
+	e.bankCardsMetric.Describe(ch)
+	e.cardExpiryMetric.Describe(ch)
 	e.domainExpiryMetric.Describe(ch)
 	e.domainStatusMetric.Describe(ch)
 	e.domainCountersMetric.Describe(ch)
+	e.domainDNSSECMetric.Describe(ch)
+	e.domainNSCountMetric.Describe(ch)
+	e.domainPriceMetric.Describe(ch)
+	e.domainPriceDiffMetric.Describe(ch)
+	e.domainAutoRenewMetric.Describe(ch)
+	e.domainLastUpdateMetric.Describe(ch)
+	e.domainLastTransferMetric.Describe(ch)
+	e.domainPrivacyMetric.Describe(ch)
+	e.domainContactMetric.Describe(ch)
+	e.domainsExpiringMetric.Describe(ch)
+	e.dnsRecordInfoMetric.Describe(ch)
+	e.dnsRecordTTLMetric.Describe(ch)
 	e.projectAmountMetric.Describe(ch)
 	e.projectDiskUsageMetric.Describe(ch)
 	e.projectDiskLimitMetric.Describe(ch)
 	e.projectBwUsageMetric.Describe(ch)
 	e.projectBwLimitMetric.Describe(ch)
+	e.projectCountMetric.Describe(ch)
+	e.servicesCountMetric.Describe(ch)
 	e.serverRAMMetric.Describe(ch)
 	e.serverCoresMetric.Describe(ch)
 	e.serverStatusMetric.Describe(ch)
@@ -512,34 +1829,161 @@ func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
 	e.cloudQuotaMetric.Describe(ch)
 	e.cloudSummaryMetric.Describe(ch)
 	e.cloudInstanceInfoMetric.Describe(ch)
+	e.cloudInstanceFieldsMetric.Describe(ch)
+	e.cloudInstanceImageMetric.Describe(ch)
+	e.cloudInstanceStatusMetric.Describe(ch)
+	e.cloudTransitionMetric.Describe(ch)
+	e.cloudInstanceCreatedMetric.Describe(ch)
+	e.cloudInstanceFixedIPMetric.Describe(ch)
+	e.cloudInstanceFloatIPMetric.Describe(ch)
+	e.floatingIPUtilMetric.Describe(ch)
+	e.floatingIPQuotaUsedMetric.Describe(ch)
+	e.floatingIPQuotaLimitMetric.Describe(ch)
 	e.vpsServerStatusMetric.Describe(ch)
 	e.vpsServerRamMetric.Describe(ch)
 	e.vpsServerCoresMetric.Describe(ch)
+	e.vpsServerCreatedMetric.Describe(ch)
+	e.vpsServerExpiryMetric.Describe(ch)
 	e.vpsServerDiskMetric.Describe(ch)
 	e.vpsServerBackupMetric.Describe(ch)
-	e.vpsServerIpsProtectMetric.Describe(ch)
+	e.vpsBackupsTotalMetric.Describe(ch)
 	e.vpsServerAmountMetric.Describe(ch)
+	e.vpsServerCountMetric.Describe(ch)
+	e.vpsBackupStatusMetric.Describe(ch)
+	e.vpsLatestBackupAgeMetric.Describe(ch)
+	e.vpsHasBackupMetric.Describe(ch)
+	e.vpsServerImageMetric.Describe(ch)
 	e.k8sClusterCountMetric.Describe(ch)
 	e.k8sClusterStatusMetric.Describe(ch)
 	e.k8sClusterNodesMetric.Describe(ch)
 	e.k8sClusterMastersMetric.Describe(ch)
+	e.k8sClusterAPIHealthyMetric.Describe(ch)
 	e.k8sNodeGroupStatusMetric.Describe(ch)
 	e.k8sNodeGroupNodesMetric.Describe(ch)
+	e.k8sNodeGroupReadyNodesMetric.Describe(ch)
 	e.k8sNodeGroupCoresMetric.Describe(ch)
 	e.k8sNodeGroupRAMMetric.Describe(ch)
+	e.k8sNodeGroupDiskMetric.Describe(ch)
+	e.k8sTemplateCoresMetric.Describe(ch)
+	e.k8sTemplateRAMMetric.Describe(ch)
+	e.k8sProjectQuotaLimitMetric.Describe(ch)
+	e.k8sProjectQuotaUsedMetric.Describe(ch)
+	ch <- e.k8sProjectStatusDesc
+	ch <- e.k8sProjectTypeDesc
 	e.lbaasLoadBalancerCountMetric.Describe(ch)
 	e.lbaasLoadBalancerStatusMetric.Describe(ch)
 	e.lbaasListenersCountMetric.Describe(ch)
 	e.lbaasPoolsCountMetric.Describe(ch)
 	e.lbaasMembersCountMetric.Describe(ch)
+	e.lbaasListenersAccountMetric.Describe(ch)
+	e.lbaasPoolsAccountMetric.Describe(ch)
+	e.lbaasMembersAccountMetric.Describe(ch)
+	e.lbaasMemberHealthMetric.Describe(ch)
+	e.lbaasUnhealthyMetric.Describe(ch)
 	e.lbaasFlavorMetric.Describe(ch)
 	e.lbaasFloatingIPMetric.Describe(ch)
+	e.lbaasProvisioningAgeMetric.Describe(ch)
 }
 
-// Collect implements prometheus.Collector
+// Collect implements prometheus.Collector.
+// Concurrent scrapes share a single in-progress collection and each
+// receives the same snapshot of its results. Scrapes that joined a
+// collection are counted in pskz_scrape_shared_total, which shows up
+// from the next snapshot on.
 func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
+	collected := false
+	result, _, _ := e.group.Do("collect", func() (interface{}, error) {
+		collected = true
+		return e.collectSnapshot(), nil
+	})
+	if !collected {
+		e.scrapeSharedMetric.Inc()
+	}
+
+	for _, m := range result.([]prometheus.Metric) {
+		ch <- m
+	}
+}
+
+// collectSnapshot runs a collection and returns a point-in-time copy of all metrics
+func (e *Exporter) collectSnapshot() []prometheus.Metric {
+	// Concurrent scrapes share one collection, so the lock is only held
+	// by status page requests, which wait for a collection to finish
+	waitStart := time.Now()
 	e.mutex.Lock()
 	defer e.mutex.Unlock()
+	e.mutexWaitMetric.Set(time.Since(waitStart).Seconds())
+
+	metrics := make(chan prometheus.Metric)
+	done := make(chan []prometheus.Metric)
+	go func() {
+		var snapshot []prometheus.Metric
+		for m := range metrics {
+			snapshot = append(snapshot, newSnapshotMetric(m))
+		}
+		done <- snapshot
+	}()
+
+	e.collectLocked(metrics)
+	close(metrics)
+	return <-done
+}
+
+// collectLocked collects all metrics, applying debouncing and zero dropping
+// if enabled and the per-family series limit. The caller must hold e.mutex.
+func (e *Exporter) collectLocked(ch chan<- prometheus.Metric) {
+	collected := time.Now()
+	metrics := make(chan prometheus.Metric)
+	done := make(chan struct{})
+	go func() {
+		for m := range metrics {
+			if e.debouncer != nil {
+				m = e.debouncer.apply(m)
+			}
+			if e.dropZeroSeries && dropZero(m) {
+				continue
+			}
+			if e.metricTimestamps {
+				m = e.withTimestamp(m, collected)
+			}
+			if e.relabeler != nil {
+				if m = e.relabeler.add(m); m == nil {
+					continue
+				}
+			}
+			if e.cardinality.allow(m) {
+				ch <- m
+			}
+		}
+		close(done)
+	}()
+
+	if e.debouncer != nil {
+		e.debouncer.begin()
+	}
+	e.cardinality.begin()
+
+	e.collect(metrics)
+	close(metrics)
+	<-done
+
+	if e.debouncer != nil {
+		e.debouncer.end()
+	}
+	// Relabeled series are sent once all series that may be combined with them are known
+	if e.relabeler != nil {
+		for _, m := range e.relabeler.flush() {
+			if e.cardinality.allow(m) {
+				ch <- m
+			}
+		}
+	}
+	e.cardinality.hits.Collect(ch)
+}
+
+// collect fetches data from the API and sends the resulting metrics to ch
+func (e *Exporter) collect(ch chan<- prometheus.Metric) {
+	e.scrapeAttemptsMetric.Inc()
 
 	start := time.Now()
 	defer func() {
@@ -547,254 +1991,521 @@ func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
 		e.scrapeDurationMetric.Set(duration)
 	}()
 
-	// Reset all metrics before collecting new data
+	// Expose the time since the previous scrape, once there has been one
+	if !e.lastScrape.IsZero() {
+		e.scrapeIntervalMetric.Set(start.Sub(e.lastScrape).Seconds())
+		e.scrapeIntervalMetric.Collect(ch)
+	}
+	e.lastScrape = start
+
+	// Report the circuit breakers at the end of every scrape, including failed ones
+	defer e.collectCircuitStates(ch)
+
+	// Identify the backend so series from different exporters can be told apart
+	e.targetInfoMetric.WithLabelValues(sanitizeLabelValue(e.client.BaseURL())).Set(1)
+	e.targetInfoMetric.Collect(ch)
+	e.exporterInfoMetric.Collect(ch)
+	e.tokenSourceMetric.Collect(ch)
+	if !e.configLoadedAt.IsZero() {
+		e.configAgeMetric.Set(time.Since(e.configLoadedAt).Seconds())
+		e.configAgeMetric.Collect(ch)
+	}
+
+	// Reset all metrics before collecting new data.
+	// Metrics of data groups are reset by processGroup and resetGroup.
+	// A non-JSON response is only reported for the scrape it occurred in
+	if e.fetchErrors[htmlResponseError] {
+		e.lastScrapeErrorMetric.WithLabelValues(htmlResponseError).Set(0)
+	}
+	e.fetchErrors = make(map[string]bool)
+	e.regions = make(map[string]string)
+	e.groupsServed = make(map[string]int)
+	e.scrapeErrorsMetric.Set(0)
+	e.schemaMissingMetric.Reset()
 	e.prepayMetric.Reset()
+	e.prepayChangeMetric.Reset()
 	e.creditMetric.Reset()
 	e.debtMetric.Reset()
 	e.bonusMetric.Reset()
 	e.blockedMetric.Reset()
+	e.creditOverdueMetric.Reset()
+	e.accountPlanMetric.Reset()
+	e.verificationExpiryMetric.Reset()
+	e.bankCardsMetric.Reset()
+	e.cardExpiryMetric.Reset()
 	e.domainExpiryMetric.Reset()
 	e.domainStatusMetric.Reset()
 	e.domainCountersMetric.Reset()
-	e.projectAmountMetric.Reset()
-	e.projectDiskUsageMetric.Reset()
-	e.projectDiskLimitMetric.Reset()
-	e.projectBwUsageMetric.Reset()
-	e.projectBwLimitMetric.Reset()
+	e.domainDNSSECMetric.Reset()
+	e.domainNSCountMetric.Reset()
+	e.domainPriceMetric.Reset()
+	e.domainPriceDiffMetric.Reset()
+	e.domainAutoRenewMetric.Reset()
+	e.domainLastUpdateMetric.Reset()
+	e.domainLastTransferMetric.Reset()
+	e.domainPrivacyMetric.Reset()
+	e.domainContactMetric.Reset()
+	e.domainsExpiringMetric.Reset()
+	e.dnsRecordInfoMetric.Reset()
+	e.dnsRecordTTLMetric.Reset()
 	e.serverRAMMetric.Reset()
 	e.serverCoresMetric.Reset()
 	e.serverStatusMetric.Reset()
 	e.serverIPCountMetric.Reset()
-	e.invoiceCountersMetric.Reset()
-	e.invoiceAmountMetric.Reset()
 	e.cloudQuotaMetric.Reset()
 	e.cloudSummaryMetric.Reset()
 	e.cloudInstanceInfoMetric.Reset()
+	e.cloudInstanceFieldsMetric.Reset()
+	e.cloudInstanceImageMetric.Reset()
+	e.cloudInstanceStatusMetric.Reset()
+	e.cloudTransitionMetric.Reset()
+	e.cloudInstanceCreatedMetric.Reset()
+	e.cloudInstanceFixedIPMetric.Reset()
+	e.cloudInstanceFloatIPMetric.Reset()
+	e.floatingIPUtilMetric.Reset()
+	e.floatingIPQuotaUsedMetric.Reset()
+	e.floatingIPQuotaLimitMetric.Reset()
 	e.vpsServerStatusMetric.Reset()
 	e.vpsServerRamMetric.Reset()
 	e.vpsServerCoresMetric.Reset()
+	e.vpsServerCreatedMetric.Reset()
+	e.vpsServerExpiryMetric.Reset()
 	e.vpsServerDiskMetric.Reset()
 	e.vpsServerBackupMetric.Reset()
-	e.vpsServerIpsProtectMetric.Reset()
+	e.vpsBackupsTotalMetric.Reset()
 	e.vpsServerAmountMetric.Reset()
-	e.k8sClusterCountMetric.Reset()
-	e.k8sClusterStatusMetric.Reset()
-	e.k8sClusterNodesMetric.Reset()
-	e.k8sClusterMastersMetric.Reset()
-	e.k8sNodeGroupStatusMetric.Reset()
-	e.k8sNodeGroupNodesMetric.Reset()
-	e.k8sNodeGroupCoresMetric.Reset()
-	e.k8sNodeGroupRAMMetric.Reset()
-	e.lbaasLoadBalancerCountMetric.Reset()
-	e.lbaasLoadBalancerStatusMetric.Reset()
-	e.lbaasListenersCountMetric.Reset()
-	e.lbaasPoolsCountMetric.Reset()
-	e.lbaasMembersCountMetric.Reset()
-	e.lbaasFlavorMetric.Reset()
-	e.lbaasFloatingIPMetric.Reset()
+	e.vpsServerCountMetric.Reset()
+	e.vpsBackupStatusMetric.Reset()
+	e.vpsLatestBackupAgeMetric.Reset()
+	e.vpsHasBackupMetric.Reset()
+	e.k8sProjectQuotaLimitMetric.Reset()
+	e.k8sProjectQuotaUsedMetric.Reset()
+	e.vpsServerImageMetric.Reset()
 
 	// Collect information about balance
-	balanceData, err := e.client.GetAccountBalance()
-	if err != nil {
-		log.Printf("Error getting extended account balance: %v", err)
-		e.lastScrapeErrorMetric.WithLabelValues("extended_balance_fetch_error").Set(1)
-	} else {
-		e.lastScrapeErrorMetric.WithLabelValues("extended_balance_fetch_error").Set(0)
-		e.processAccountBalanceInfo(balanceData)
-	}
-
-	// Alternative method for getting the balance (in case the previous one didn't work)
-	balance, err := e.client.GetBalance()
-	if err != nil {
-		log.Printf("Error getting balance: %v", err)
-		e.lastScrapeErrorMetric.WithLabelValues("balance_fetch_error").Set(1)
-		e.scrapeSuccessMetric.Set(0)
-
-		// Collect error metrics
-		e.scrapeDurationMetric.Collect(ch)
-		e.scrapeSuccessMetric.Collect(ch)
-		e.lastScrapeErrorMetric.Collect(ch)
-		return
+	if e.enabled("balance", true) {
+		balanceData, err := e.client.GetAccountBalance()
+		if err != nil {
+			log.Printf("Error getting extended account balance: %v", err)
+			e.recordFetchError("extended_balance_fetch_error", err)
+		} else {
+			e.setFetchError("extended_balance_fetch_error", false)
+			e.processAccountBalanceInfo(balanceData)
+		}
+
+		// Alternative method for getting the balance (in case the previous one didn't work)
+		balance, err := e.client.GetBalance()
+		if err != nil {
+			log.Printf("Error getting balance: %v", err)
+			e.recordFetchError("balance_fetch_error", err)
+		} else {
+			e.setFetchError("balance_fetch_error", false)
+
+			e.prepayMetric.WithLabelValues("default").Set(balance.Data.Account.Balance.Prepay)
+			e.creditMetric.WithLabelValues("default").Set(balance.Data.Account.Balance.Credit)
+			e.debtMetric.WithLabelValues("default").Set(balance.Data.Account.Balance.Debt)
+		}
+
+		e.updatePrepayChange()
 	}
-	e.lastScrapeErrorMetric.WithLabelValues("balance_fetch_error").Set(0)
 
-	e.prepayMetric.WithLabelValues("default").Set(balance.Data.Account.Balance.Prepay)
-	e.creditMetric.WithLabelValues("default").Set(balance.Data.Account.Balance.Credit)
-	e.debtMetric.WithLabelValues("default").Set(balance.Data.Account.Balance.Debt)
+	// Collect account verification expiry if enabled
+	if e.enabled("account_verification", e.collectAccountVerification) {
+		verification, err := e.client.GetAccountVerification()
+		if err != nil {
+			log.Printf("Error getting account verification: %v", err)
+			e.recordFetchError("account_verification_fetch_error", err)
+		} else {
+			e.setFetchError("account_verification_fetch_error", false)
+			if expiresAt, ok := parseTimeValue(verification.ExpiresAt); ok {
+				e.verificationExpiryMetric.WithLabelValues().Set(float64(expiresAt.Unix()))
+			}
+		}
+	}
+
+	// Collect payment cards if enabled
+	if e.enabled("bank_cards", e.collectBankCards) {
+		cards, err := e.client.GetBankCards()
+		if err != nil {
+			log.Printf("Error getting bank cards: %v", err)
+			e.recordFetchError("bank_cards_fetch_error", err)
+		} else {
+			e.setFetchError("bank_cards_fetch_error", false)
+			e.processBankCards(cards)
+		}
+	}
 
 	// Collect domain counters
-	domainCounters, err := e.client.GetDomainCounters()
-	if err != nil {
-		log.Printf("Error getting domain counters: %v", err)
-		e.lastScrapeErrorMetric.WithLabelValues("domain_counters_fetch_error").Set(1)
-	} else {
-		e.lastScrapeErrorMetric.WithLabelValues("domain_counters_fetch_error").Set(0)
-		e.processDomainCounters(domainCounters)
+	if e.enabled("domain_counters", true) {
+		domainCounters, err := e.client.GetDomainCounters()
+		if err != nil {
+			log.Printf("Error getting domain counters: %v", err)
+			e.recordFetchError("domain_counters_fetch_error", err)
+		} else {
+			e.setFetchError("domain_counters_fetch_error", false)
+			e.processDomainCounters(domainCounters)
+		}
 	}
 
 	// Collect information about domains
-	domains, err := e.client.GetDomains()
-	if err != nil {
-		log.Printf("Error getting domains: %v", err)
-		e.lastScrapeErrorMetric.WithLabelValues("domains_fetch_error").Set(1)
-		e.scrapeSuccessMetric.Set(0)
-
-		// Collect error metrics
-		e.scrapeDurationMetric.Collect(ch)
-		e.scrapeSuccessMetric.Collect(ch)
-		e.lastScrapeErrorMetric.Collect(ch)
-		e.prepayMetric.Collect(ch)
-		e.creditMetric.Collect(ch)
-		e.debtMetric.Collect(ch)
-		return
+	if e.enabled("domains", true) {
+		domains, err := e.client.GetDomains(e.domainStatuses, e.collectDomainAutoRenew)
+		if err != nil {
+			log.Printf("Error getting domains: %v", err)
+			e.recordFetchError("domains_fetch_error", err)
+		} else {
+			e.setFetchError("domains_fetch_error", false)
+
+			// Domains expiring within each bucket, reported as 0 when there are none
+			expiring := make([]int, len(e.domainExpiryBuckets))
+
+			for _, domain := range domains.Data.Domains.Items {
+				expiryTime, err := parseExpiryDate(domain.ExpiryDate)
+				if err != nil {
+					log.Printf("Error parsing expiry date for domain %s: %v", domain.Name, err)
+					continue
+				}
+
+				// Calculate the number of days until expiration
+				daysUntilExpiry := time.Until(expiryTime).Hours() / 24
+				e.domainExpiryMetric.WithLabelValues(domain.Name).Set(daysUntilExpiry)
+
+				for i, days := range e.domainExpiryBuckets {
+					if daysUntilExpiry >= 0 && daysUntilExpiry <= float64(days) {
+						expiring[i]++
+					}
+				}
+
+				var status float64
+				switch domain.Status {
+				case "active":
+					status = 1
+				case "expired":
+					status = 0
+				default:
+					status = -1
+				}
+				e.domainStatusMetric.WithLabelValues(domain.Name, domain.Status).Set(status)
+
+				// Nameserver and DNSSEC details are only set when the API returns them
+				if domain.Nameservers != nil {
+					e.domainNSCountMetric.WithLabelValues(domain.Name).Set(float64(len(domain.Nameservers)))
+				}
+
+				if domain.DNSSEC != nil {
+					dnssec := 0.0
+					if *domain.DNSSEC {
+						dnssec = 1
+					}
+					e.domainDNSSECMetric.WithLabelValues(domain.Name).Set(dnssec)
+				}
+
+				if e.collectDomainAutoRenew && domain.AutoRenew != nil {
+					autoRenew := 0.0
+					if *domain.AutoRenew {
+						autoRenew = 1
+					}
+					e.domainAutoRenewMetric.WithLabelValues(domain.Name).Set(autoRenew)
+				}
+
+				// Whois timestamps are optional and only exported when present
+				if domain.Whois != nil {
+					if domain.Whois.Update != nil {
+						e.domainLastUpdateMetric.WithLabelValues(domain.Name).Set(float64(domain.Whois.Update.Unix))
+					}
+					if domain.Whois.Transfer != nil {
+						e.domainLastTransferMetric.WithLabelValues(domain.Name).Set(float64(domain.Whois.Transfer.Unix))
+					}
+					if domain.Whois.ContactWhois != nil {
+						e.processDomainContacts(domain.Name, domain.Whois.ContactWhois)
+					}
+				}
+			}
+
+			for i, days := range e.domainExpiryBuckets {
+				e.domainsExpiringMetric.WithLabelValues(fmt.Sprintf("%dd", days)).Set(float64(expiring[i]))
+			}
+		}
 	}
-	e.lastScrapeErrorMetric.WithLabelValues("domains_fetch_error").Set(0)
 
-	for _, domain := range domains.Data.Domains.Items {
-		expiryTime, err := time.Parse("2006-01-02", domain.ExpiryDate)
+	// Collect domain prices if enabled
+	if e.enabled("domain_prices", e.collectDomainPrices) {
+		prices, err := e.client.GetPrices()
 		if err != nil {
-			log.Printf("Error parsing expiry date for domain %s: %v", domain.Name, err)
-			continue
+			log.Printf("Error getting domain prices: %v", err)
+			e.recordFetchError("domain_prices_fetch_error", err)
+		} else {
+			e.setFetchError("domain_prices_fetch_error", false)
+			e.processDomainPrices(prices)
 		}
+	}
 
-		// Calculate the number of days until expiration
-		daysUntilExpiry := time.Until(expiryTime).Hours() / 24
-		e.domainExpiryMetric.WithLabelValues(domain.Name).Set(daysUntilExpiry)
-
-		var status float64
-		switch domain.Status {
-		case "active":
-			status = 1
-		case "expired":
-			status = 0
-		default:
-			status = -1
+	// Collect records of the configured DNS zones
+	if len(e.dnsZones) > 0 && e.enabled("dns_records", true) {
+		dnsFailed := false
+		for _, zone := range e.dnsZones {
+			records, err := e.client.GetDNSRecords(zone)
+			if err != nil {
+				log.Printf("Error getting DNS records for zone %s: %v", zone, err)
+				e.recordFetchError("dns_records_fetch_error", err)
+				dnsFailed = true
+				continue
+			}
+			e.processDNSRecords(zone, records)
+		}
+		if !dnsFailed {
+			e.setFetchError("dns_records_fetch_error", false)
 		}
-		e.domainStatusMetric.WithLabelValues(domain.Name, domain.Status).Set(status)
 	}
 
 	// Collect information about projects
-	projectsData, err := e.client.GetProjects([]string{"Active"}, 100)
-	if err != nil {
-		log.Printf("Error getting projects: %v", err)
-		e.lastScrapeErrorMetric.WithLabelValues("projects_fetch_error").Set(1)
-	} else {
-		e.lastScrapeErrorMetric.WithLabelValues("projects_fetch_error").Set(0)
-		e.processProjectsInfo(projectsData)
+	if e.enabled("projects", true) {
+		projectsData, err := e.client.GetProjects([]string{"Active", "Suspended"}, 100)
+		if err != nil {
+			log.Printf("Error getting projects: %v", err)
+			e.recordFetchError("projects_fetch_error", err)
+			e.resetGroup("projects", err)
+		} else {
+			e.setFetchError("projects_fetch_error", false)
+			e.processGroup("projects", projectsData, func() {
+				e.processProjectsInfo(projectsData)
+			})
+		}
 	}
 
 	// Collect information about invoices
-	invoicesData, err := e.client.GetInvoices("Unpaid", 20)
-	if err != nil {
-		log.Printf("Error getting invoices: %v", err)
-		e.lastScrapeErrorMetric.WithLabelValues("invoices_fetch_error").Set(1)
-	} else {
-		e.lastScrapeErrorMetric.WithLabelValues("invoices_fetch_error").Set(0)
-		e.processInvoicesInfo(invoicesData)
+	if e.enabled("invoices", true) {
+		invoicesData, err := e.client.GetInvoices("Unpaid", 20)
+		if err != nil {
+			log.Printf("Error getting invoices: %v", err)
+			e.recordFetchError("invoices_fetch_error", err)
+			e.resetGroup("invoices", err)
+		} else {
+			e.setFetchError("invoices_fetch_error", false)
+			e.processGroup("invoices", invoicesData, func() {
+				e.processInvoicesInfo(invoicesData)
+			})
+		}
 	}
 
 	// Collect information about cloud resources
-	cloudResources, err := e.client.GetCloudResources()
-	if err != nil {
-		log.Printf("Error getting cloud resources: %v", err)
-		e.lastScrapeErrorMetric.WithLabelValues("cloud_resources_fetch_error").Set(1)
-	} else {
-		e.lastScrapeErrorMetric.WithLabelValues("cloud_resources_fetch_error").Set(0)
-		e.processCloudResources(cloudResources)
-	}
-
-	// Collect detailed information about cloud instances
-	cloudInstances, err := e.client.GetCloudInstances()
-	if err != nil {
-		log.Printf("Error getting cloud instances: %v", err)
-		e.lastScrapeErrorMetric.WithLabelValues("cloud_instances_fetch_error").Set(1)
-	} else {
-		e.lastScrapeErrorMetric.WithLabelValues("cloud_instances_fetch_error").Set(0)
-		e.processCloudInstances(cloudInstances)
-	}
-
-	// Collect information about VPS servers
-	vpsData, err := e.client.GetVpsServersStatus()
-	if err != nil {
-		log.Printf("Error getting VPS server status: %v", err)
-		e.lastScrapeErrorMetric.WithLabelValues("vps_servers_fetch_error").Set(1)
-	} else {
-		e.lastScrapeErrorMetric.WithLabelValues("vps_servers_fetch_error").Set(0)
-		e.processVpsServersStatus(vpsData)
+	if e.enabled("cloud_resources", true) {
+		cloudResources, err := e.client.GetCloudResources()
+		if err != nil {
+			log.Printf("Error getting cloud resources: %v", err)
+			e.recordFetchError("cloud_resources_fetch_error", err)
+		} else {
+			e.setFetchError("cloud_resources_fetch_error", false)
+			e.processCloudResources(cloudResources)
+		}
 	}
 
-	// If service ID is specified, collect information about VPC servers
-	if e.serviceID != "" {
-		// Collect information about VPC servers
-		vpcServers, err := e.client.GetCloudServers(e.serviceID)
+	// Collect detailed information about cloud instances, only the monitored ones if configured
+	if e.enabled("cloud_instances", true) {
+		var cloudInstances map[string]interface{}
+		var err error
+		if len(e.cloudInstances) > 0 {
+			cloudInstances, err = e.fetchCloudInstances()
+		} else {
+			cloudInstances, err = e.client.GetCloudInstances(e.cloudInstanceFields)
+		}
 		if err != nil {
-			log.Printf("Error getting VPC servers: %v", err)
-			e.lastScrapeErrorMetric.WithLabelValues("vpc_servers_fetch_error").Set(1)
+			log.Printf("Error getting cloud instances: %v", err)
+			e.recordFetchError("cloud_instances_fetch_error", err)
 		} else {
-			e.lastScrapeErrorMetric.WithLabelValues("vpc_servers_fetch_error").Set(0)
-			e.processServerInfo(vpcServers, "vpc")
+			e.setFetchError("cloud_instances_fetch_error", false)
+			e.processCloudInstances(cloudInstances)
 		}
+	}
 
-		// Collect information about VPS servers
-		vpsServers, err := e.client.GetVPSServers(e.serviceID)
+	// Collect information about VPS servers, only the monitored ones if configured
+	if e.enabled("vps_servers", true) {
+		var vpsData map[string]interface{}
+		var err error
+		if len(e.vpsServers) > 0 {
+			vpsData, err = e.fetchVPSServers()
+		} else {
+			vpsData, err = e.client.GetVpsServersStatus()
+		}
 		if err != nil {
-			log.Printf("Error getting VPS servers: %v", err)
-			e.lastScrapeErrorMetric.WithLabelValues("vps_servers_fetch_error").Set(1)
+			log.Printf("Error getting VPS server status: %v", err)
+			e.recordFetchError("vps_servers_fetch_error", err)
 		} else {
-			e.lastScrapeErrorMetric.WithLabelValues("vps_servers_fetch_error").Set(0)
-			e.processServerInfo(vpsServers, "vps")
+			e.setFetchError("vps_servers_fetch_error", false)
+			e.processVpsServersStatus(vpsData)
+		}
+	}
+
+	// If service ID is specified, collect information about VPC servers
+	if e.serviceID != "" && e.enabled("cloud_servers", true) {
+		// Collect information about VPC servers
+		if e.client.ServiceEnabled("cloud") {
+			vpcServers, err := e.client.GetCloudServers(e.serviceID)
+			if err != nil {
+				log.Printf("Error getting VPC servers: %v", err)
+				e.recordFetchError("vpc_servers_fetch_error", err)
+			} else {
+				e.setFetchError("vpc_servers_fetch_error", false)
+				e.processServerInfo(vpcServers, "vpc")
+			}
+		}
+
+		// Collect information about VPS servers
+		if e.client.ServiceEnabled("vps") {
+			vpsServers, err := e.client.GetVPSServers(e.serviceID)
+			if err != nil {
+				log.Printf("Error getting VPS servers: %v", err)
+				e.recordFetchError("service_vps_servers_fetch_error", err)
+			} else {
+				e.setFetchError("service_vps_servers_fetch_error", false)
+				e.processServerInfo(vpsServers, "vps")
+			}
 		}
 	}
 
 	// Collect information about Kubernetes clusters
-	k8sClusters, err := e.client.GetK8SClusters()
-	if err != nil {
-		log.Printf("Error getting K8S clusters: %v", err)
-		e.lastScrapeErrorMetric.WithLabelValues("k8s_clusters_fetch_error").Set(1)
-	} else {
-		e.lastScrapeErrorMetric.WithLabelValues("k8s_clusters_fetch_error").Set(0)
-		e.processK8SClusters(k8sClusters)
+	if e.enabled("k8s_clusters", true) {
+		k8sClusters, err := e.client.GetK8SClusters()
+		if err != nil {
+			log.Printf("Error getting K8S clusters: %v", err)
+			e.recordFetchError("k8s_clusters_fetch_error", err)
+			e.resetGroup("k8s_clusters", err)
+		} else {
+			e.setFetchError("k8s_clusters_fetch_error", false)
+			e.processGroup("k8s_clusters", k8sClusters, func() {
+				e.processK8SClusters(k8sClusters)
+			})
+		}
 	}
 
 	// Collect information about Kubernetes projects
-	k8sProjects, err := e.client.GetK8SProjects()
-	if err != nil {
-		log.Printf("Error getting K8S projects: %v", err)
-		e.lastScrapeErrorMetric.WithLabelValues("k8s_projects_fetch_error").Set(1)
-	} else {
-		e.lastScrapeErrorMetric.WithLabelValues("k8s_projects_fetch_error").Set(0)
-		e.processK8SProjects(k8sProjects, ch)
+	if e.enabled("k8s_projects", true) {
+		k8sProjects, err := e.client.GetK8SProjects()
+		if err != nil {
+			log.Printf("Error getting K8S projects: %v", err)
+			e.recordFetchError("k8s_projects_fetch_error", err)
+		} else {
+			e.setFetchError("k8s_projects_fetch_error", false)
+			e.processK8SProjects(k8sProjects, ch)
+		}
 	}
 
 	// Collect information about LBaaS load balancers
-	lbaasData, err := e.client.GetLBaaSLoadBalancers()
-	if err != nil {
-		log.Printf("Error getting LBaaS load balancers: %v", err)
-		e.lastScrapeErrorMetric.WithLabelValues("lbaas_loadbalancers_fetch_error").Set(1)
-	} else {
-		e.lastScrapeErrorMetric.WithLabelValues("lbaas_loadbalancers_fetch_error").Set(0)
-		e.processLBaaSData(lbaasData)
+	if e.enabled("lbaas", true) {
+		lbaasData, err := e.client.GetLBaaSLoadBalancers()
+		if err != nil {
+			log.Printf("Error getting LBaaS load balancers: %v", err)
+			e.recordFetchError("lbaas_loadbalancers_fetch_error", err)
+			if e.resetGroup("lbaas", err) {
+				e.lbaasPending = nil
+			}
+		} else {
+			e.setFetchError("lbaas_loadbalancers_fetch_error", false)
+			e.processGroup("lbaas", lbaasData, func() {
+				e.processLBaaSData(lbaasData)
+			})
+		}
+	}
+
+	// Provisioning ages change with time, so they are refreshed even when the group is unchanged
+	e.lbaasProvisioningAgeMetric.Reset()
+	for _, lb := range e.lbaasPending {
+		e.lbaasProvisioningAgeMetric.WithLabelValues(lb.id, lb.name, lb.status).Set(time.Since(lb.since).Seconds())
+	}
+
+	// Roll the per-resource series up into account-level totals
+	e.updateAccountTotals()
+
+	success := e.scrapeSucceeded()
+	e.recordScrapeOutcome(success)
+	if success {
+		e.scrapeSuccessesMetric.Inc()
 	}
 
-	e.scrapeSuccessMetric.Set(1)
+	// Expose rate limits reported by the API during this scrape
+	e.rateLimitRemainingMetric.Reset()
+	e.rateLimitResetMetric.Reset()
+	for endpoint, limit := range e.client.RateLimits() {
+		e.rateLimitRemainingMetric.WithLabelValues(endpoint).Set(limit.Remaining)
+		if !limit.Reset.IsZero() {
+			e.rateLimitResetMetric.WithLabelValues(endpoint).Set(float64(limit.Reset.Unix()))
+		}
+	}
+
+	// Expose display names of the regions in use
+	e.regionInfoMetric.Reset()
+	for regionID, name := range e.regions {
+		e.regionInfoMetric.WithLabelValues(sanitizeLabelValues(regionID, name)...).Set(1)
+	}
+	e.regionsInUseMetric.Set(float64(len(e.regions)))
+
+	// Observe the sizes of the API responses received since the previous scrape
+	for _, size := range e.client.TakeResponseSizes() {
+		e.responseBytesMetric.WithLabelValues(size.Endpoint).Observe(float64(size.Bytes))
+	}
+
+	// Report how many data groups were kept from the previous scrape
+	for _, source := range []string{"cached", "fresh"} {
+		e.cacheServedMetric.WithLabelValues(source).Set(float64(e.groupsServed[source]))
+	}
 
 	// Collect all metrics
+	e.responseBytesMetric.Collect(ch)
+	e.cacheServedMetric.Collect(ch)
+	e.rateLimitRemainingMetric.Collect(ch)
+	e.regionInfoMetric.Collect(ch)
+	e.regionsInUseMetric.Collect(ch)
+	e.rateLimitResetMetric.Collect(ch)
+	ch <- prometheus.MustNewConstMetric(e.reauthDesc, prometheus.CounterValue, float64(e.client.ReauthCount()))
 	e.scrapeDurationMetric.Collect(ch)
+	e.mutexWaitMetric.Collect(ch)
 	e.scrapeSuccessMetric.Collect(ch)
+	e.scrapeErrorRatioMetric.Collect(ch)
+	e.scrapeAttemptsMetric.Collect(ch)
+	e.scrapeSharedMetric.Collect(ch)
+	e.scrapeSuccessesMetric.Collect(ch)
 	e.lastScrapeErrorMetric.Collect(ch)
+	e.monitoredErrorMetric.Collect(ch)
+	e.schemaMissingMetric.Collect(ch)
+	e.scrapeErrorsMetric.Collect(ch)
+	e.updateDataAge()
+	e.dataAgeMetric.Collect(ch)
 	e.prepayMetric.Collect(ch)
+	e.prepayChangeMetric.Collect(ch)
 	e.creditMetric.Collect(ch)
 	e.debtMetric.Collect(ch)
 	e.bonusMetric.Collect(ch)
 	e.blockedMetric.Collect(ch)
+	e.creditOverdueMetric.Collect(ch)
+	e.accountPlanMetric.Collect(ch)
+	e.accountCoresMetric.Collect(ch)
+	e.accountRAMMetric.Collect(ch)
+	e.accountInstancesMetric.Collect(ch)
+	e.verificationExpiryMetric.Collect(ch)
+	e.bankCardsMetric.Collect(ch)
+	e.cardExpiryMetric.Collect(ch)
 	e.domainExpiryMetric.Collect(ch)
 	e.domainStatusMetric.Collect(ch)
 	e.domainCountersMetric.Collect(ch)
+	e.domainDNSSECMetric.Collect(ch)
+	e.domainNSCountMetric.Collect(ch)
+	e.domainPriceMetric.Collect(ch)
+	e.domainPriceDiffMetric.Collect(ch)
+	e.domainAutoRenewMetric.Collect(ch)
+	e.domainLastUpdateMetric.Collect(ch)
+	e.domainLastTransferMetric.Collect(ch)
+	e.domainPrivacyMetric.Collect(ch)
+	e.domainContactMetric.Collect(ch)
+	e.domainsExpiringMetric.Collect(ch)
+	e.dnsRecordInfoMetric.Collect(ch)
+	e.dnsRecordTTLMetric.Collect(ch)
 	e.projectAmountMetric.Collect(ch)
 	e.projectDiskUsageMetric.Collect(ch)
 	e.projectDiskLimitMetric.Collect(ch)
 	e.projectBwUsageMetric.Collect(ch)
 	e.projectBwLimitMetric.Collect(ch)
+	e.projectCountMetric.Collect(ch)
+	e.servicesCountMetric.Collect(ch)
 	e.serverRAMMetric.Collect(ch)
 	e.serverCoresMetric.Collect(ch)
 	e.serverStatusMetric.Collect(ch)
@@ -804,28 +2515,73 @@ func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
 	e.cloudQuotaMetric.Collect(ch)
 	e.cloudSummaryMetric.Collect(ch)
 	e.cloudInstanceInfoMetric.Collect(ch)
+	e.cloudInstanceFieldsMetric.Collect(ch)
+	e.cloudInstanceImageMetric.Collect(ch)
+	e.cloudInstanceStatusMetric.Collect(ch)
+	e.cloudTransitionMetric.Collect(ch)
+	e.cloudInstanceCreatedMetric.Collect(ch)
+	e.cloudInstanceFixedIPMetric.Collect(ch)
+	e.cloudInstanceFloatIPMetric.Collect(ch)
+	e.floatingIPUtilMetric.Collect(ch)
+	e.floatingIPQuotaUsedMetric.Collect(ch)
+	e.floatingIPQuotaLimitMetric.Collect(ch)
 	e.vpsServerStatusMetric.Collect(ch)
 	e.vpsServerRamMetric.Collect(ch)
 	e.vpsServerCoresMetric.Collect(ch)
+	e.vpsServerCreatedMetric.Collect(ch)
+	e.vpsServerExpiryMetric.Collect(ch)
 	e.vpsServerDiskMetric.Collect(ch)
 	e.vpsServerBackupMetric.Collect(ch)
-	e.vpsServerIpsProtectMetric.Collect(ch)
+	e.vpsBackupsTotalMetric.Collect(ch)
 	e.vpsServerAmountMetric.Collect(ch)
+	e.vpsServerCountMetric.Collect(ch)
+	e.vpsBackupStatusMetric.Collect(ch)
+	e.vpsLatestBackupAgeMetric.Collect(ch)
+	e.vpsHasBackupMetric.Collect(ch)
+	e.vpsServerImageMetric.Collect(ch)
 	e.k8sClusterCountMetric.Collect(ch)
 	e.k8sClusterStatusMetric.Collect(ch)
 	e.k8sClusterNodesMetric.Collect(ch)
 	e.k8sClusterMastersMetric.Collect(ch)
+	e.k8sClusterAPIHealthyMetric.Collect(ch)
 	e.k8sNodeGroupStatusMetric.Collect(ch)
 	e.k8sNodeGroupNodesMetric.Collect(ch)
+	e.k8sNodeGroupReadyNodesMetric.Collect(ch)
 	e.k8sNodeGroupCoresMetric.Collect(ch)
 	e.k8sNodeGroupRAMMetric.Collect(ch)
+	e.k8sNodeGroupDiskMetric.Collect(ch)
+	e.k8sTemplateCoresMetric.Collect(ch)
+	e.k8sTemplateRAMMetric.Collect(ch)
+	e.k8sProjectQuotaLimitMetric.Collect(ch)
+	e.k8sProjectQuotaUsedMetric.Collect(ch)
 	e.lbaasLoadBalancerCountMetric.Collect(ch)
 	e.lbaasLoadBalancerStatusMetric.Collect(ch)
 	e.lbaasListenersCountMetric.Collect(ch)
 	e.lbaasPoolsCountMetric.Collect(ch)
 	e.lbaasMembersCountMetric.Collect(ch)
+	e.lbaasListenersAccountMetric.Collect(ch)
+	e.lbaasPoolsAccountMetric.Collect(ch)
+	e.lbaasMembersAccountMetric.Collect(ch)
+	e.lbaasMemberHealthMetric.Collect(ch)
+	e.lbaasUnhealthyMetric.Collect(ch)
 	e.lbaasFlavorMetric.Collect(ch)
 	e.lbaasFloatingIPMetric.Collect(ch)
+	e.lbaasProvisioningAgeMetric.Collect(ch)
+}
+
+// updatePrepayChange sets the change of every prepay balance fetched in this
+// scrape since the previous scrape that fetched it. Balances that could not be
+// fetched keep their previous value for the next comparison.
+func (e *Exporter) updatePrepayChange() {
+	for _, sample := range gaugeSamples(e.prepayMetric) {
+		account := sample.labels["account"]
+		change := 0.0
+		if previous, ok := e.previousPrepay[account]; ok {
+			change = sample.value - previous
+		}
+		e.prepayChangeMetric.WithLabelValues(account).Set(change)
+		e.previousPrepay[account] = sample.value
+	}
 }
 
 // processAccountBalanceInfo processes account balance information
@@ -834,24 +2590,28 @@ func (e *Exporter) processAccountBalanceInfo(balanceData map[string]interface{})
 	data, ok := balanceData["data"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for account balance: data field missing")
+		e.markSchemaFieldMissing("account_balance", "data")
 		return
 	}
 
 	account, ok := data["account"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for account balance: account field missing")
+		e.markSchemaFieldMissing("account_balance", "data.account")
 		return
 	}
 
 	current, ok := account["current"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for account balance: current field missing")
+		e.markSchemaFieldMissing("account_balance", "data.account.current")
 		return
 	}
 
 	info, ok := current["info"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for account balance: info field missing")
+		e.markSchemaFieldMissing("account_balance", "data.account.current.info")
 		return
 	}
 
@@ -868,6 +2628,15 @@ func (e *Exporter) processAccountBalanceInfo(balanceData map[string]interface{})
 		e.blockedMetric.WithLabelValues("account").Set(blocked)
 	}
 
+	// The plan is either a name or an object with a name
+	plan, _ := info["plan"].(string)
+	if planObject, ok := info["plan"].(map[string]interface{}); ok {
+		plan, _ = planObject["name"].(string)
+	}
+	if plan != "" {
+		e.accountPlanMetric.WithLabelValues(plan).Set(1)
+	}
+
 	// Process credit
 	if credit, ok := info["credit"].(map[string]interface{}); ok {
 		if creditVal, ok := credit["credit"].(float64); ok {
@@ -881,7 +2650,40 @@ func (e *Exporter) processAccountBalanceInfo(balanceData map[string]interface{})
 		if availableCredit, ok := credit["availableCredit"].(float64); ok {
 			e.creditMetric.WithLabelValues("account_available_credit").Set(float64(availableCredit))
 		}
+
+		// Credit is overdue once its repayment date has passed while some of it is still used.
+		// Without a repayment date or credit amount the series is omitted.
+		if mustPaidTill, ok := parseTimeValue(credit["mustPaidTill"]); ok {
+			if used, ok := credit["credit"].(float64); ok {
+				overdue := 0.0
+				if used > 0 && time.Now().After(mustPaidTill) {
+					overdue = 1
+				}
+				e.creditOverdueMetric.WithLabelValues("account").Set(overdue)
+			}
+		}
+	}
+}
+
+// defaultDomainExpiryBuckets are the expiry windows in days used when none are configured
+var defaultDomainExpiryBuckets = []int{30, 60, 90}
+
+// expiryBuckets returns the positive, distinct buckets in ascending order,
+// or the default buckets if there are none
+func expiryBuckets(buckets []int) []int {
+	seen := make(map[int]bool)
+	var result []int
+	for _, days := range buckets {
+		if days > 0 && !seen[days] {
+			seen[days] = true
+			result = append(result, days)
+		}
 	}
+	if len(result) == 0 {
+		return defaultDomainExpiryBuckets
+	}
+	sort.Ints(result)
+	return result
 }
 
 // processDomainCounters processes domain counters
@@ -890,24 +2692,28 @@ func (e *Exporter) processDomainCounters(domainCountersData map[string]interface
 	data, ok := domainCountersData["data"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for domain counters: data field missing")
+		e.markSchemaFieldMissing("domain_counters", "data")
 		return
 	}
 
 	account, ok := data["account"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for domain counters: account field missing")
+		e.markSchemaFieldMissing("domain_counters", "data.account")
 		return
 	}
 
 	domains, ok := account["domains"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for domain counters: domains field missing")
+		e.markSchemaFieldMissing("domain_counters", "data.account.domains")
 		return
 	}
 
 	stats, ok := domains["stats"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for domain counters: stats field missing")
+		e.markSchemaFieldMissing("domain_counters", "data.account.domains.stats")
 		return
 	}
 
@@ -929,39 +2735,139 @@ func (e *Exporter) processDomainCounters(domainCountersData map[string]interface
 	}
 }
 
+// processBankCards processes the payment card count and the expiry of each listed card
+func (e *Exporter) processBankCards(cards *client.BankCards) {
+	e.bankCardsMetric.WithLabelValues().Set(float64(cards.Count))
+
+	for _, card := range cards.Cards {
+		label := card.Mask
+		if label == "" {
+			label = card.ID
+		}
+		if expiresAt, ok := parseTimeValue(card.ExpiresAt); ok && label != "" {
+			e.cardExpiryMetric.WithLabelValues(label).Set(float64(expiresAt.Unix()))
+		}
+	}
+}
+
+// processDomainPrices processes domain prices of all zones
+func (e *Exporter) processDomainPrices(prices *client.PricesResponse) {
+	for zone, price := range prices.Data.Domains.Prices {
+		zone = strings.TrimPrefix(zone, "zone_")
+
+		// Unparseable prices are NaN and are left out
+		if !math.IsNaN(price.Register) {
+			e.domainPriceMetric.WithLabelValues(zone, "register").Set(price.Register)
+		}
+		if !math.IsNaN(price.Renew) {
+			e.domainPriceMetric.WithLabelValues(zone, "renew").Set(price.Renew)
+		}
+		if !math.IsNaN(price.Transfer) {
+			e.domainPriceMetric.WithLabelValues(zone, "transfer").Set(price.Transfer)
+		}
+
+		if !math.IsNaN(price.Register) && !math.IsNaN(price.Renew) {
+			e.domainPriceDiffMetric.WithLabelValues(zone).Set(price.Register - price.Renew)
+		}
+	}
+}
+
+// processDNSRecords processes the records of a DNS zone
+func (e *Exporter) processDNSRecords(zone string, records []client.DNSRecord) {
+	for _, record := range records {
+		labels := sanitizeLabelValues(zone, record.Type, record.Name, record.Value)
+		e.dnsRecordInfoMetric.WithLabelValues(labels...).Set(1)
+		e.dnsRecordTTLMetric.WithLabelValues(labels...).Set(record.TTL)
+	}
+}
+
+// Scrape success policies. Every collector runs regardless of the others'
+// failures; the policy only decides what pskz_scrape_success reports.
+const (
+	// SuccessPolicyAll counts a scrape as successful only if every data source was fetched
+	SuccessPolicyAll = "all"
+	// SuccessPolicyAny counts a scrape as successful if at least one data source was fetched
+	SuccessPolicyAny = "any"
+)
+
+// ValidateSuccessPolicy checks that policy is a supported scrape success policy
+func ValidateSuccessPolicy(policy string) error {
+	switch policy {
+	case "", SuccessPolicyAll, SuccessPolicyAny:
+		return nil
+	}
+	return fmt.Errorf("unsupported success policy %q (expected %s or %s)", policy, SuccessPolicyAll, SuccessPolicyAny)
+}
+
+// ValidateProjectLabel checks that strategy is a supported project label strategy
+func ValidateProjectLabel(strategy string) error {
+	switch strategy {
+	case "", "id", "name", "domain", "domain-id":
+		return nil
+	}
+	return fmt.Errorf("unsupported project label %q (expected id, name, domain or domain-id)", strategy)
+}
+
+// projectLabelValue returns the project label for the given strategy.
+// It falls back to the ID when the field the strategy needs is empty.
+func projectLabelValue(strategy, id, name, domain string) string {
+	switch strategy {
+	case "id":
+		return id
+	case "name":
+		if name != "" {
+			return name
+		}
+	case "domain":
+		if domain != "" {
+			return domain
+		}
+	default:
+		if domain != "" {
+			return domain + "-" + id
+		}
+	}
+	return id
+}
+
 // processProjectsInfo processes information about projects
 func (e *Exporter) processProjectsInfo(projectsData map[string]interface{}) {
 	// Unpack nested objects
 	data, ok := projectsData["data"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for projects: data field missing")
+		e.markSchemaFieldMissing("projects", "data")
 		return
 	}
 
 	account, ok := data["account"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for projects: account field missing")
+		e.markSchemaFieldMissing("projects", "data.account")
 		return
 	}
 
 	services, ok := account["services"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for projects: services field missing")
+		e.markSchemaFieldMissing("projects", "data.account.services")
 		return
 	}
 
-	pagination, ok := services["pagination"].(map[string]interface{})
+	pagination, ok := e.paginationOf(services, "projects")
 	if !ok {
-		log.Printf("Invalid data structure for projects: pagination field missing")
 		return
 	}
 
-	items, ok := pagination["items"].([]interface{})
+	items, ok := e.paginationItems(pagination, "projects")
 	if !ok {
-		log.Printf("Invalid data structure for projects: items field missing or not an array")
 		return
 	}
 
+	// Count projects by status and services by type
+	statusCounts := make(map[string]int)
+	typeCounts := make(map[string]int)
+
 	// Set project metrics
 	for _, item := range items {
 		projectItem, ok := item.(map[string]interface{})
@@ -970,42 +2876,63 @@ func (e *Exporter) processProjectsInfo(projectsData map[string]interface{}) {
 			continue
 		}
 
+		status, ok := projectItem["status"].(string)
+		if !ok {
+			status = "unknown"
+		}
+		statusCounts[status]++
+
+		// Services are counted as unknown when the API does not report their type
+		serviceType, ok := projectItem["type"].(string)
+		if !ok || serviceType == "" {
+			serviceType = "unknown"
+		}
+		typeCounts[serviceType]++
+
 		// Get project ID
 		projectId, ok := projectItem["id"].(float64)
 		if !ok {
 			log.Printf("Invalid project item: id missing or not a number")
+			e.markSchemaFieldMissing("projects", "data.account.services.pagination.items.id")
 			continue
 		}
 
 		projectIdStr := fmt.Sprintf("%d", int(projectId))
-
-		// Get project domain for better identification
-		domain, ok := projectItem["domain"].(string)
-		if ok {
-			projectIdStr = fmt.Sprintf("%s-%d", domain, int(projectId))
-		}
+		domain, _ := projectItem["domain"].(string)
+		name, _ := projectItem["name"].(string)
+		projectLabel := projectLabelValue(e.projectLabel, projectIdStr, name, domain)
 
 		// Set project metrics
 		if price, ok := projectItem["price"].(float64); ok {
-			e.projectAmountMetric.WithLabelValues(projectIdStr).Set(price)
+			e.projectAmountMetric.WithLabelValues(projectLabel, projectIdStr).Set(price)
 		}
 
 		if diskUsage, ok := projectItem["diskUsage"].(float64); ok {
-			e.projectDiskUsageMetric.WithLabelValues(projectIdStr).Set(diskUsage)
+			e.projectDiskUsageMetric.WithLabelValues(projectLabel, projectIdStr).Set(diskUsage)
 		}
 
 		if diskLimit, ok := projectItem["diskLimit"].(float64); ok {
-			e.projectDiskLimitMetric.WithLabelValues(projectIdStr).Set(diskLimit)
+			e.projectDiskLimitMetric.WithLabelValues(projectLabel, projectIdStr).Set(diskLimit)
 		}
 
 		if bandwidthUsage, ok := projectItem["bandwidthUsage"].(float64); ok {
-			e.projectBwUsageMetric.WithLabelValues(projectIdStr).Set(bandwidthUsage)
+			e.projectBwUsageMetric.WithLabelValues(projectLabel, projectIdStr).Set(bandwidthUsage)
 		}
 
 		if bandwidthLimit, ok := projectItem["bandwidthLimit"].(float64); ok {
-			e.projectBwLimitMetric.WithLabelValues(projectIdStr).Set(bandwidthLimit)
+			e.projectBwLimitMetric.WithLabelValues(projectLabel, projectIdStr).Set(bandwidthLimit)
 		}
 	}
+
+	// Set metrics for project counts by status
+	for status, count := range statusCounts {
+		e.projectCountMetric.WithLabelValues(status).Set(float64(count))
+	}
+
+	// Set metrics for service counts by type
+	for serviceType, count := range typeCounts {
+		e.servicesCountMetric.WithLabelValues(serviceType).Set(float64(count))
+	}
 }
 
 // processInvoicesInfo processes information about invoices
@@ -1014,18 +2941,21 @@ func (e *Exporter) processInvoicesInfo(invoicesData map[string]interface{}) {
 	data, ok := invoicesData["data"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for invoices: data field missing")
+		e.markSchemaFieldMissing("invoices", "data")
 		return
 	}
 
 	account, ok := data["account"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for invoices: account field missing")
+		e.markSchemaFieldMissing("invoices", "data.account")
 		return
 	}
 
 	invoice, ok := account["invoice"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for invoices: invoice field missing")
+		e.markSchemaFieldMissing("invoices", "data.account.invoice")
 		return
 	}
 
@@ -1050,32 +2980,37 @@ func (e *Exporter) processInvoicesInfo(invoicesData map[string]interface{}) {
 	}
 
 	// Process invoices
-	pagination, ok := invoice["pagination"].(map[string]interface{})
-	if ok {
-		items, ok := pagination["items"].([]interface{})
-		if ok {
-			// Process each invoice
-			for _, item := range items {
-				invoiceItem, ok := item.(map[string]interface{})
-				if !ok {
-					log.Printf("Invalid invoice item: not an object")
-					continue
-				}
+	pagination, ok := e.paginationOf(invoice, "invoices")
+	if !ok {
+		return
+	}
 
-				// Get invoice ID
-				invoiceId, ok := invoiceItem["id"].(float64)
-				if !ok {
-					log.Printf("Invalid invoice item: id missing or not a number")
-					continue
-				}
+	items, ok := e.paginationItems(pagination, "invoices")
+	if !ok {
+		return
+	}
 
-				invoiceIdStr := fmt.Sprintf("%d", int(invoiceId))
+	// Process each invoice
+	for _, item := range items {
+		invoiceItem, ok := item.(map[string]interface{})
+		if !ok {
+			log.Printf("Invalid invoice item: not an object")
+			continue
+		}
 
-				// Set invoice metrics
-				if total, ok := invoiceItem["total"].(float64); ok {
-					e.invoiceAmountMetric.WithLabelValues(invoiceIdStr).Set(total)
-				}
-			}
+		// Get invoice ID
+		invoiceId, ok := invoiceItem["id"].(float64)
+		if !ok {
+			log.Printf("Invalid invoice item: id missing or not a number")
+			e.markSchemaFieldMissing("invoices", "data.account.invoice.pagination.items.id")
+			continue
+		}
+
+		invoiceIdStr := fmt.Sprintf("%d", int(invoiceId))
+
+		// Set invoice metrics
+		if total, ok := invoiceItem["total"].(float64); ok {
+			e.invoiceAmountMetric.WithLabelValues(invoiceIdStr).Set(total)
 		}
 	}
 }
@@ -1087,30 +3022,31 @@ func (e *Exporter) processServerInfo(serverData map[string]interface{}, serviceT
 	data, ok := serverData["data"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for server info: data field missing")
+		e.markSchemaFieldMissing("server_info", "data")
 		return
 	}
 
 	vpc, ok := data["vpc"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for server info: vpc field missing")
+		e.markSchemaFieldMissing("server_info", "data.vpc")
 		return
 	}
 
 	instance, ok := vpc["instance"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for server info: instance field missing")
+		e.markSchemaFieldMissing("server_info", "data.vpc.instance")
 		return
 	}
 
-	pagination, ok := instance["pagination"].(map[string]interface{})
+	pagination, ok := e.paginationOf(instance, "server info")
 	if !ok {
-		log.Printf("Invalid data structure for server info: pagination field missing")
 		return
 	}
 
-	items, ok := pagination["items"].([]interface{})
+	items, ok := e.paginationItems(pagination, "server info")
 	if !ok {
-		log.Printf("Invalid data structure for server info: items field missing or not an array")
 		return
 	}
 
@@ -1124,6 +3060,7 @@ func (e *Exporter) processServerInfo(serverData map[string]interface{}, serviceT
 		instanceName, ok := server["instanceName"].(string)
 		if !ok {
 			log.Printf("Invalid server item: instanceName missing or not a string")
+			e.markSchemaFieldMissing("server_info", "data.vpc.instance.pagination.items.instanceName")
 			continue
 		}
 
@@ -1165,43 +3102,78 @@ func (e *Exporter) processCloudResources(cloudResourcesData map[string]interface
 	data, ok := cloudResourcesData["data"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for cloud resources: data field missing")
+		e.markSchemaFieldMissing("cloud_resources", "data")
 		return
 	}
 
 	vpc, ok := data["vpc"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for cloud resources: vpc field missing")
+		e.markSchemaFieldMissing("cloud_resources", "data.vpc")
 		return
 	}
 
 	service, ok := vpc["service"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for cloud resources: service field missing")
+		e.markSchemaFieldMissing("cloud_resources", "data.vpc.service")
 		return
 	}
 
+	// Floating IP quota per region, used for the utilization ratio
+	floatingIPLimits := make(map[string]float64)
+	floatingIPUsed := make(map[string]float64)
+
+	// Quotas are either a single object or a list with one object per region
+	var quotaSets []interface{}
+	switch quotas := service["quotas"].(type) {
+	case map[string]interface{}:
+		quotaSets = []interface{}{quotas}
+	case []interface{}:
+		quotaSets = quotas
+	}
+
 	// Process quotas
-	quotas, ok := service["quotas"].(map[string]interface{})
-	if ok {
+	for _, set := range quotaSets {
+		quotas, ok := set.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		regionID, ok := quotas["regionId"].(string)
+		if !ok {
+			regionID = "unknown"
+		}
+		e.observeRegion(regionID, quotas["regionName"])
+
 		resources, ok := quotas["resources"].([]interface{})
-		if ok {
-			for _, res := range resources {
-				resource, ok := res.(map[string]interface{})
-				if !ok {
-					continue
-				}
+		if !ok {
+			continue
+		}
 
-				name, ok := resource["name"].(string)
-				if !ok {
-					continue
-				}
+		for _, res := range resources {
+			resource, ok := res.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			name, ok := resource["name"].(string)
+			if !ok {
+				continue
+			}
+			floating := strings.Contains(strings.ToLower(name), "floating")
 
-				if used, ok := resource["used"].(float64); ok {
-					e.cloudQuotaMetric.WithLabelValues(fmt.Sprintf("%s_used", name)).Set(used)
+			if used, ok := resource["used"].(float64); ok {
+				e.cloudQuotaMetric.WithLabelValues(fmt.Sprintf("%s_used", name), regionID).Set(used)
+				if floating {
+					floatingIPUsed[regionID] = used
 				}
+			}
 
-				if limit, ok := resource["limit"].(float64); ok {
-					e.cloudQuotaMetric.WithLabelValues(fmt.Sprintf("%s_limit", name)).Set(limit)
+			if limit, ok := resource["limit"].(float64); ok {
+				e.cloudQuotaMetric.WithLabelValues(fmt.Sprintf("%s_limit", name), regionID).Set(limit)
+				if floating {
+					floatingIPLimits[regionID] = limit
 				}
 			}
 		}
@@ -1236,6 +3208,15 @@ func (e *Exporter) processCloudResources(cloudResourcesData map[string]interface
 
 		if floatingIpsCount, ok := summary["floatingIpsCount"].(float64); ok {
 			e.cloudSummaryMetric.WithLabelValues("floating_ips_count").Set(floatingIpsCount)
+
+			// The account-wide count stands in for the quota usage of a single region
+			if len(floatingIPLimits) == 1 {
+				for regionID := range floatingIPLimits {
+					if _, ok := floatingIPUsed[regionID]; !ok {
+						floatingIPUsed[regionID] = floatingIpsCount
+					}
+				}
+			}
 		}
 
 		if securityGroupsCount, ok := summary["securityGroupsCount"].(float64); ok {
@@ -1247,6 +3228,21 @@ func (e *Exporter) processCloudResources(cloudResourcesData map[string]interface
 		}
 	}
 
+	// The floating IP quota is also exposed on its own, being the one most often exhausted
+	for regionID, used := range floatingIPUsed {
+		e.floatingIPQuotaUsedMetric.WithLabelValues(regionID).Set(used)
+	}
+	for regionID, limit := range floatingIPLimits {
+		e.floatingIPQuotaLimitMetric.WithLabelValues(regionID).Set(limit)
+	}
+
+	// Guard against a missing or zero quota
+	for regionID, limit := range floatingIPLimits {
+		if used, ok := floatingIPUsed[regionID]; ok && limit > 0 {
+			e.floatingIPUtilMetric.WithLabelValues(regionID).Set(used / limit)
+		}
+	}
+
 	// Process instance information
 	instanceInfo, ok := service["instanceInfo"].(map[string]interface{})
 	if ok {
@@ -1263,39 +3259,77 @@ func (e *Exporter) processCloudResources(cloudResourcesData map[string]interface
 	}
 }
 
+// fieldLabelNames converts API field names such as "imageName" to label names such as "image_name"
+func fieldLabelNames(fields []string) []string {
+	labels := make([]string, 0, len(fields))
+	for _, field := range fields {
+		var b strings.Builder
+		for i, r := range field {
+			if unicode.IsUpper(r) {
+				if i > 0 {
+					b.WriteByte('_')
+				}
+				r = unicode.ToLower(r)
+			}
+			b.WriteRune(r)
+		}
+		labels = append(labels, b.String())
+	}
+	return labels
+}
+
+// transitionalInstanceStatuses are the cloud instance states of operations in
+// progress. An instance that stays in one of them usually has a stuck operation.
+var transitionalInstanceStatuses = map[string]bool{
+	"BUILD":         true,
+	"REBUILD":       true,
+	"REBOOT":        true,
+	"HARD_REBOOT":   true,
+	"RESIZE":        true,
+	"VERIFY_RESIZE": true,
+	"REVERT_RESIZE": true,
+	"MIGRATING":     true,
+}
+
 // processCloudInstances processes detailed information about cloud instances
 func (e *Exporter) processCloudInstances(instancesData map[string]interface{}) {
 	// Unpack nested objects
 	data, ok := instancesData["data"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for cloud instances: data field missing")
+		e.markSchemaFieldMissing("cloud_instances", "data")
 		return
 	}
 
 	vpc, ok := data["vpc"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for cloud instances: vpc field missing")
+		e.markSchemaFieldMissing("cloud_instances", "data.vpc")
 		return
 	}
 
 	instance, ok := vpc["instance"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for cloud instances: instance field missing")
+		e.markSchemaFieldMissing("cloud_instances", "data.vpc.instance")
 		return
 	}
 
-	pagination, ok := instance["pagination"].(map[string]interface{})
+	pagination, ok := e.paginationOf(instance, "cloud instances")
 	if !ok {
-		log.Printf("Invalid data structure for cloud instances: pagination field missing")
 		return
 	}
 
-	items, ok := pagination["items"].([]interface{})
+	items, ok := e.paginationItems(pagination, "cloud instances")
 	if !ok {
-		log.Printf("Invalid data structure for cloud instances: items field missing or not an array")
 		return
 	}
 
+	// Transitional states are always exposed so that a drop to zero is visible
+	for status := range transitionalInstanceStatuses {
+		e.cloudTransitionMetric.WithLabelValues(status).Set(0)
+	}
+
 	// Process each instance
 	for _, item := range items {
 		instanceItem, ok := item.(map[string]interface{})
@@ -1308,6 +3342,7 @@ func (e *Exporter) processCloudInstances(instancesData map[string]interface{}) {
 		instanceName, ok := instanceItem["instanceName"].(string)
 		if !ok {
 			log.Printf("Invalid instance item: instanceName missing or not a string")
+			e.markSchemaFieldMissing("cloud_instances", "data.vpc.instance.pagination.items.instanceName")
 			continue
 		}
 
@@ -1326,6 +3361,33 @@ func (e *Exporter) processCloudInstances(instancesData map[string]interface{}) {
 				statusValue = -1
 			}
 			e.cloudInstanceInfoMetric.WithLabelValues(instanceName, "status").Set(statusValue)
+			e.cloudInstanceStatusMetric.WithLabelValues(instanceName, status).Set(1)
+			if transitionalInstanceStatuses[status] {
+				e.cloudTransitionMetric.WithLabelValues(status).Inc()
+			}
+		}
+
+		// Image is only exported when the API returns it
+		if image, ok := instanceItem["imageName"].(string); ok && image != "" {
+			e.cloudInstanceImageMetric.WithLabelValues(instanceName, image).Set(1)
+		}
+
+		// Expose the additional fields as labels, empty when the API returns none
+		if len(e.cloudInstanceFields) > 0 {
+			values := []string{instanceName}
+			for _, field := range e.cloudInstanceFields {
+				value := ""
+				if raw, ok := instanceItem[field]; ok && raw != nil {
+					value = fmt.Sprint(raw)
+				}
+				values = append(values, value)
+			}
+			e.cloudInstanceFieldsMetric.WithLabelValues(values...).Set(1)
+		}
+
+		// Set creation time if available
+		if createdAt, ok := parseTimeValue(instanceItem["createdAt"]); ok {
+			e.cloudInstanceCreatedMetric.WithLabelValues(instanceName).Set(float64(createdAt.Unix()))
 		}
 
 		// Set metrics for flavor
@@ -1360,8 +3422,41 @@ func (e *Exporter) processCloudInstances(instancesData map[string]interface{}) {
 		floatingIps, ok := instanceItem["floatingIpsArray"].([]interface{})
 		if ok {
 			e.cloudInstanceInfoMetric.WithLabelValues(instanceName, "floating_ips_count").Set(float64(len(floatingIps)))
+			for version, count := range countIPVersions(floatingIps) {
+				e.cloudInstanceFloatIPMetric.WithLabelValues(instanceName, version).Set(float64(count))
+			}
+		}
+
+		// Fixed IP addresses are only present when the API reports them
+		if fixedIps, ok := instanceItem["fixedIpsArray"].([]interface{}); ok {
+			for version, count := range countIPVersions(fixedIps) {
+				e.cloudInstanceFixedIPMetric.WithLabelValues(instanceName, version).Set(float64(count))
+			}
+		}
+	}
+}
+
+// countIPVersions counts IP addresses, plain or in CIDR notation, by version ("4" or "6").
+// Both versions are always present; entries that are not addresses are counted as "unknown".
+func countIPVersions(ips []interface{}) map[string]int {
+	counts := map[string]int{"4": 0, "6": 0}
+	for _, raw := range ips {
+		address, _ := raw.(string)
+		address = strings.TrimSpace(address)
+		ip := net.ParseIP(address)
+		if ip == nil {
+			ip, _, _ = net.ParseCIDR(address)
+		}
+		switch {
+		case ip == nil:
+			counts["unknown"]++
+		case ip.To4() != nil:
+			counts["4"]++
+		default:
+			counts["6"]++
 		}
 	}
+	return counts
 }
 
 // processVpsServersStatus processes information about VPS servers
@@ -1370,34 +3465,38 @@ func (e *Exporter) processVpsServersStatus(vpsData map[string]interface{}) {
 	data, ok := vpsData["data"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for VPS servers: data field missing")
+		e.markSchemaFieldMissing("vps_servers", "data")
 		return
 	}
 
 	vps, ok := data["vps"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for VPS servers: vps field missing")
+		e.markSchemaFieldMissing("vps_servers", "data.vps")
 		return
 	}
 
 	server, ok := vps["server"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for VPS servers: server field missing")
+		e.markSchemaFieldMissing("vps_servers", "data.vps.server")
 		return
 	}
 
-	pagination, ok := server["pagination"].(map[string]interface{})
+	pagination, ok := e.paginationOf(server, "VPS servers")
 	if !ok {
-		log.Printf("Invalid data structure for VPS servers: pagination field missing")
 		return
 	}
 
-	// Count servers by status
+	// Count servers by status and by region
 	statusCounts := make(map[string]int)
+	regionCounts := make(map[[2]string]int)
+	backupsFailed := false
+	var backupsSize float64
 
 	// Process servers
-	items, ok := pagination["items"].([]interface{})
+	items, ok := e.paginationItems(pagination, "VPS servers")
 	if !ok {
-		log.Printf("Invalid data structure for VPS servers: items field missing or not an array")
 		return
 	}
 
@@ -1426,8 +3525,34 @@ func (e *Exporter) processVpsServersStatus(vpsData map[string]interface{}) {
 		}
 		e.vpsServerStatusMetric.WithLabelValues(serverIdStr, serverName, status).Set(statusValue)
 
+		// Image is only exported when the API returns it
+		if image, ok := serverInfo["imageName"].(string); ok && image != "" {
+			e.vpsServerImageMetric.WithLabelValues(serverIdStr, image).Set(1)
+		}
+
+		// Dates are only exported when the API returns them
+		if createdAt, ok := parseTimeValue(serverInfo["createdAt"]); ok {
+			e.vpsServerCreatedMetric.WithLabelValues(serverIdStr, serverName).Set(float64(createdAt.Unix()))
+		}
+		if paidTill, ok := parseTimeValue(serverInfo["paidTill"]); ok {
+			e.vpsServerExpiryMetric.WithLabelValues(serverIdStr, serverName).Set(float64(paidTill.Unix()))
+		}
+
 		// Get region
 		regionId, _ := serverInfo["regionId"].(string)
+		regionCounts[[2]string{regionId, status}]++
+		e.observeRegion(regionId, serverInfo["regionName"])
+
+		// Collect backups of the server
+		if serverId > 0 {
+			backups, err := e.client.GetVpsBackups(int(serverId), regionId)
+			if err != nil {
+				log.Printf("Error getting backups for VPS server %s: %v", serverIdStr, err)
+				backupsFailed = true
+			} else {
+				backupsSize += e.processVpsBackups(serverIdStr, serverName, backups)
+			}
+		}
 
 		// Get tariff info if available
 		if tariff, ok := serverInfo["tariff"].(map[string]interface{}); ok {
@@ -1443,10 +3568,135 @@ func (e *Exporter) processVpsServersStatus(vpsData map[string]interface{}) {
 		}
 	}
 
+	e.setFetchError("vps_backups_fetch_error", backupsFailed)
+
+	// The total is left out if it would miss the backups of some servers
+	if !backupsFailed {
+		e.vpsBackupsTotalMetric.WithLabelValues("account").Set(backupsSize)
+	}
+
 	// Set status counters
 	for status, count := range statusCounts {
 		e.vpsServerStatusMetric.WithLabelValues("all", "total", status).Set(float64(count))
 	}
+
+	// Set per-region counters
+	for key, count := range regionCounts {
+		e.vpsServerCountMetric.WithLabelValues(key[0], key[1]).Set(float64(count))
+	}
+}
+
+// processVpsBackups processes information about backups of a VPS server
+// and returns the total size of its backups in GB
+func (e *Exporter) processVpsBackups(serverIdStr, serverName string, backupsData map[string]interface{}) float64 {
+	// Unpack nested objects
+	// Response structure: {"data": {"vps": {"backup": {"pagination": {"items": [...]}}}}}
+	data, ok := backupsData["data"].(map[string]interface{})
+	if !ok {
+		log.Printf("Invalid data structure for VPS backups: data field missing")
+		e.markSchemaFieldMissing("vps_backups", "data")
+		return 0
+	}
+
+	vps, ok := data["vps"].(map[string]interface{})
+	if !ok {
+		log.Printf("Invalid data structure for VPS backups: vps field missing")
+		e.markSchemaFieldMissing("vps_backups", "data.vps")
+		return 0
+	}
+
+	backup, ok := vps["backup"].(map[string]interface{})
+	if !ok {
+		log.Printf("Invalid data structure for VPS backups: backup field missing")
+		e.markSchemaFieldMissing("vps_backups", "data.vps.backup")
+		return 0
+	}
+
+	pagination, ok := e.paginationOf(backup, "VPS backups")
+	if !ok {
+		return 0
+	}
+
+	items, ok := e.paginationItems(pagination, "VPS backups")
+	if !ok {
+		return 0
+	}
+
+	// Track the newest backup and the total size of the server's backups
+	var latest time.Time
+	var size float64
+
+	for _, item := range items {
+		backupItem, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if createdAt, ok := parseTimeValue(backupItem["backupCreatedAt"]); ok && createdAt.After(latest) {
+			latest = createdAt
+		}
+
+		if backupSize, ok := backupItem["size"].(float64); ok {
+			size += backupSize
+		}
+
+		name, _ := backupItem["name"].(string)
+		if name == "" {
+			name, _ = backupItem["_id"].(string)
+		}
+
+		status, ok := backupItem["status"].(string)
+		if !ok {
+			status = "unknown"
+		}
+
+		// Set backup status metric (1 if completed, 0 otherwise)
+		var statusValue float64
+		switch strings.ToLower(status) {
+		case "completed", "complete", "success", "available":
+			statusValue = 1
+		default:
+			statusValue = 0
+		}
+		e.vpsBackupStatusMetric.WithLabelValues(serverIdStr, serverName, name, status).Set(statusValue)
+	}
+
+	e.vpsServerBackupMetric.WithLabelValues(serverName).Set(size)
+
+	// Servers without backups get no age series, only has_backup=0
+	if len(items) == 0 {
+		e.vpsHasBackupMetric.WithLabelValues(serverIdStr, serverName).Set(0)
+		return 0
+	}
+	e.vpsHasBackupMetric.WithLabelValues(serverIdStr, serverName).Set(1)
+
+	if !latest.IsZero() {
+		e.vpsLatestBackupAgeMetric.WithLabelValues(serverIdStr, serverName).Set(time.Since(latest).Seconds())
+	}
+	return size
+}
+
+// processDomainContacts sets the privacy protection and last update of the whois
+// contacts of a domain. Contacts and fields missing from the response are skipped.
+func (e *Exporter) processDomainContacts(domain string, contacts *client.DomainContactWhois) {
+	for contact, info := range map[string]*client.WhoisContact{
+		"registrant": contacts.RegistrantContact,
+		"admin":      contacts.AdminContact,
+	} {
+		if info == nil {
+			continue
+		}
+		if info.Privacy != nil {
+			privacy := 0.0
+			if *info.Privacy {
+				privacy = 1
+			}
+			e.domainPrivacyMetric.WithLabelValues(domain, contact).Set(privacy)
+		}
+		if info.Update != nil {
+			e.domainContactMetric.WithLabelValues(domain, contact).Set(float64(info.Update.Unix))
+		}
+	}
 }
 
 // processK8SClusters processes Kubernetes clusters information
@@ -1455,24 +3705,26 @@ func (e *Exporter) processK8SClusters(k8sClustersData map[string]interface{}) {
 	data, ok := k8sClustersData["data"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for K8S clusters: data field missing")
+		e.markSchemaFieldMissing("k8s_clusters", "data")
 		return
 	}
 
 	k8saas, ok := data["k8saas"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for K8S clusters: k8saas field missing")
+		e.markSchemaFieldMissing("k8s_clusters", "data.k8saas")
 		return
 	}
 
 	cluster, ok := k8saas["cluster"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for K8S clusters: cluster field missing")
+		e.markSchemaFieldMissing("k8s_clusters", "data.k8saas.cluster")
 		return
 	}
 
-	pagination, ok := cluster["pagination"].(map[string]interface{})
+	pagination, ok := e.paginationOf(cluster, "K8S clusters")
 	if !ok {
-		log.Printf("Invalid data structure for K8S clusters: pagination field missing")
 		return
 	}
 
@@ -1483,15 +3735,19 @@ func (e *Exporter) processK8SClusters(k8sClustersData map[string]interface{}) {
 	}
 
 	// Process clusters
-	items, ok := pagination["items"].([]interface{})
+	items, ok := e.paginationItems(pagination, "K8S clusters")
 	if !ok {
-		log.Printf("Invalid data structure for K8S clusters: items field missing or not an array")
 		return
 	}
 
 	// Initialize counters for cluster statuses
 	statusCounts := make(map[string]int)
 
+	// Node group resources summed per cluster template
+	templates := make(map[string]bool)
+	templateCores := make(map[string]float64)
+	templateRAM := make(map[string]float64)
+
 	for _, item := range items {
 		clusterItem, ok := item.(map[string]interface{})
 		if !ok {
@@ -1502,6 +3758,7 @@ func (e *Exporter) processK8SClusters(k8sClustersData map[string]interface{}) {
 		clusterId, ok := clusterItem["_id"].(string)
 		if !ok {
 			log.Printf("Invalid cluster item: _id missing or not a string")
+			e.markSchemaFieldMissing("k8s_clusters", "data.k8saas.cluster.pagination.items._id")
 			continue
 		}
 
@@ -1525,6 +3782,7 @@ func (e *Exporter) processK8SClusters(k8sClustersData map[string]interface{}) {
 
 		endpointId, _ := clusterItem["endpointId"].(string)
 		regionId, _ := clusterItem["regionId"].(string)
+		e.observeRegion(regionId, clusterItem["regionName"])
 
 		// Get template name
 		templateName := "unknown"
@@ -1533,6 +3791,7 @@ func (e *Exporter) processK8SClusters(k8sClustersData map[string]interface{}) {
 				templateName = tName
 			}
 		}
+		templates[templateName] = true
 
 		// Set cluster status metric (1 for active, 0 for inactive or other)
 		var statusValue float64
@@ -1561,6 +3820,16 @@ func (e *Exporter) processK8SClusters(k8sClustersData map[string]interface{}) {
 			e.k8sClusterMastersMetric.WithLabelValues(clusterId, name).Set(masterCount)
 		}
 
+		// Set API health metric only when the health status is known
+		if healthStatus, ok := clusterItem["healthStatus"].(string); ok {
+			switch strings.ToUpper(healthStatus) {
+			case "HEALTHY":
+				e.k8sClusterAPIHealthyMetric.WithLabelValues(clusterId, name).Set(1)
+			case "UNHEALTHY":
+				e.k8sClusterAPIHealthyMetric.WithLabelValues(clusterId, name).Set(0)
+			}
+		}
+
 		// Process node groups
 		if nodeGroups, ok := clusterItem["clusterNodeGroups"].([]interface{}); ok {
 			for _, ng := range nodeGroups {
@@ -1594,7 +3863,8 @@ func (e *Exporter) processK8SClusters(k8sClustersData map[string]interface{}) {
 				).Set(nodeGroupStatusValue)
 
 				// Set node count for the group
-				if nodeCount, ok := nodeGroup["nodeCount"].(float64); ok {
+				nodeCount, ok := nodeGroup["nodeCount"].(float64)
+				if ok {
 					e.k8sNodeGroupNodesMetric.WithLabelValues(
 						clusterId,
 						name,
@@ -1603,6 +3873,16 @@ func (e *Exporter) processK8SClusters(k8sClustersData map[string]interface{}) {
 					).Set(nodeCount)
 				}
 
+				// Ready node count is only present when the API reports node readiness
+				if readyNodes, ok := nodeGroup["readyNodeCount"].(float64); ok {
+					e.k8sNodeGroupReadyNodesMetric.WithLabelValues(
+						clusterId,
+						name,
+						nodeGroupId,
+						nodeGroupName,
+					).Set(readyNodes)
+				}
+
 				// Process flavor details
 				if flavorDetailed, ok := nodeGroup["flavorDetailed"].(map[string]interface{}); ok {
 					if vcpus, ok := flavorDetailed["vcpus"].(float64); ok {
@@ -1612,6 +3892,7 @@ func (e *Exporter) processK8SClusters(k8sClustersData map[string]interface{}) {
 							nodeGroupId,
 							nodeGroupName,
 						).Set(vcpus)
+						templateCores[templateName] += vcpus * nodeCount
 					}
 
 					if ram, ok := flavorDetailed["ram"].(float64); ok {
@@ -1621,6 +3902,17 @@ func (e *Exporter) processK8SClusters(k8sClustersData map[string]interface{}) {
 							nodeGroupId,
 							nodeGroupName,
 						).Set(ram)
+						templateRAM[templateName] += ram * nodeCount
+					}
+
+					// Disk size is only present when the API reports it
+					if disk, ok := flavorDetailed["disk"].(float64); ok {
+						e.k8sNodeGroupDiskMetric.WithLabelValues(
+							clusterId,
+							name,
+							nodeGroupId,
+							nodeGroupName,
+						).Set(disk)
 					}
 				}
 			}
@@ -1631,32 +3923,48 @@ func (e *Exporter) processK8SClusters(k8sClustersData map[string]interface{}) {
 	for status, count := range statusCounts {
 		e.k8sClusterCountMetric.WithLabelValues(status).Set(float64(count))
 	}
+
+	// Set node group resources by cluster template
+	for templateName := range templates {
+		e.k8sTemplateCoresMetric.WithLabelValues(templateName).Set(templateCores[templateName])
+		e.k8sTemplateRAMMetric.WithLabelValues(templateName).Set(templateRAM[templateName])
+	}
+}
+
+// pendingLoadBalancer is a load balancer in a pending provisioning state
+type pendingLoadBalancer struct {
+	id, name, status string
+	since            time.Time
 }
 
 // processLBaaSData processes LBaaS load balancer information
 func (e *Exporter) processLBaaSData(lbaasData map[string]interface{}) {
+	e.lbaasPending = nil
+
 	// Unpack nested objects
 	data, ok := lbaasData["data"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for LBaaS data: data field missing")
+		e.markSchemaFieldMissing("lbaas", "data")
 		return
 	}
 
 	lbaas, ok := data["lbaas"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for LBaaS data: lbaas field missing")
+		e.markSchemaFieldMissing("lbaas", "data.lbaas")
 		return
 	}
 
 	loadBalancer, ok := lbaas["loadBalancer"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for LBaaS data: loadBalancer field missing")
+		e.markSchemaFieldMissing("lbaas", "data.lbaas.loadBalancer")
 		return
 	}
 
-	pagination, ok := loadBalancer["pagination"].(map[string]interface{})
+	pagination, ok := e.paginationOf(loadBalancer, "LBaaS data")
 	if !ok {
-		log.Printf("Invalid data structure for LBaaS data: pagination field missing")
 		return
 	}
 
@@ -1665,15 +3973,17 @@ func (e *Exporter) processLBaaSData(lbaasData map[string]interface{}) {
 		e.lbaasLoadBalancerCountMetric.WithLabelValues("total").Set(count)
 	}
 
-	items, ok := pagination["items"].([]interface{})
+	items, ok := e.paginationItems(pagination, "LBaaS data")
 	if !ok {
-		log.Printf("Invalid data structure for LBaaS data: items field missing or not an array")
 		return
 	}
 
 	// Initialize counters for load balancer statuses
 	statusCounts := make(map[string]int)
 
+	// Account-wide counts across all load balancers
+	var listenersTotal, poolsTotal, membersTotal int
+
 	for _, item := range items {
 		lb, ok := item.(map[string]interface{})
 		if !ok {
@@ -1684,6 +3994,7 @@ func (e *Exporter) processLBaaSData(lbaasData map[string]interface{}) {
 		id, ok := lb["_id"].(string)
 		if !ok {
 			log.Printf("Invalid load balancer item: _id missing or not a string")
+			e.markSchemaFieldMissing("lbaas", "data.lbaas.loadBalancer.pagination.items._id")
 			continue
 		}
 
@@ -1696,6 +4007,7 @@ func (e *Exporter) processLBaaSData(lbaasData map[string]interface{}) {
 		if !ok {
 			regionID = "unknown"
 		}
+		e.observeRegion(regionID, lb["regionName"])
 
 		vipAddress, ok := lb["vipAddress"].(string)
 		if !ok {
@@ -1742,6 +4054,18 @@ func (e *Exporter) processLBaaSData(lbaasData map[string]interface{}) {
 			floatingIP,
 		).Set(statusValue)
 
+		// Track load balancers stuck in a pending state, such as PENDING_CREATE or PENDING_UPDATE.
+		// Creation is timed from createdAt, other operations from updatedAt.
+		if strings.HasPrefix(status, "PENDING_") {
+			since, ok := parseTimeValue(lb["updatedAt"])
+			if status == "PENDING_CREATE" || !ok {
+				since, ok = parseTimeValue(lb["createdAt"])
+			}
+			if ok {
+				e.lbaasPending = append(e.lbaasPending, pendingLoadBalancer{id: id, name: name, status: status, since: since})
+			}
+		}
+
 		// Set flavor metric
 		flavorName, ok := lb["flavorName"].(string)
 		if ok && flavorName != "" {
@@ -1759,25 +4083,53 @@ func (e *Exporter) processLBaaSData(lbaasData map[string]interface{}) {
 		listeners, ok := lb["listeners"].([]interface{})
 		if ok {
 			e.lbaasListenersCountMetric.WithLabelValues(id, name).Set(float64(len(listeners)))
+			listenersTotal += len(listeners)
 		}
 
 		// Process pools
 		pools, ok := lb["pools"].([]interface{})
 		if ok {
 			e.lbaasPoolsCountMetric.WithLabelValues(id, name).Set(float64(len(pools)))
+			poolsTotal += len(pools)
 		}
 
-		// Process members
+		// Process members. Without member health the load balancer's health cannot be
+		// determined and it is not reported as unhealthy.
+		unhealthy := 0.0
 		members, ok := lb["members"].([]interface{})
 		if ok {
 			e.lbaasMembersCountMetric.WithLabelValues(id, name).Set(float64(len(members)))
+			membersTotal += len(members)
+
+			// Count members by operating status, skipping members without one
+			memberStates := make(map[string]int)
+			for _, m := range members {
+				member, ok := m.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if state, ok := member["operatingStatus"].(string); ok && state != "" {
+					memberStates[state]++
+				}
+			}
+			for state, count := range memberStates {
+				e.lbaasMemberHealthMetric.WithLabelValues(id, name, state).Set(float64(count))
+			}
+			if status == "ACTIVE" && len(memberStates) > 0 && memberStates["ONLINE"] == 0 {
+				unhealthy = 1
+			}
 		}
+		e.lbaasUnhealthyMetric.WithLabelValues(id, name).Set(unhealthy)
 	}
 
 	// Set metrics for load balancer counts by status
 	for status, count := range statusCounts {
 		e.lbaasLoadBalancerCountMetric.WithLabelValues(status).Set(float64(count))
 	}
+
+	e.lbaasListenersAccountMetric.WithLabelValues().Set(float64(listenersTotal))
+	e.lbaasPoolsAccountMetric.WithLabelValues().Set(float64(poolsTotal))
+	e.lbaasMembersAccountMetric.WithLabelValues().Set(float64(membersTotal))
 }
 
 // processK8SProjects processes Kubernetes projects information
@@ -1786,31 +4138,32 @@ func (e *Exporter) processK8SProjects(k8sProjectsData map[string]interface{}, ch
 	data, ok := k8sProjectsData["data"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for K8S projects: data field missing")
+		e.markSchemaFieldMissing("k8s_projects", "data")
 		return
 	}
 
 	k8saas, ok := data["k8saas"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for K8S projects: k8saas field missing")
+		e.markSchemaFieldMissing("k8s_projects", "data.k8saas")
 		return
 	}
 
 	project, ok := k8saas["project"].(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid data structure for K8S projects: project field missing")
+		e.markSchemaFieldMissing("k8s_projects", "data.k8saas.project")
 		return
 	}
 
-	pagination, ok := project["pagination"].(map[string]interface{})
+	pagination, ok := e.paginationOf(project, "K8S projects")
 	if !ok {
-		log.Printf("Invalid data structure for K8S projects: pagination field missing")
 		return
 	}
 
 	// Process items
-	items, ok := pagination["items"].([]interface{})
+	items, ok := e.paginationItems(pagination, "K8S projects")
 	if !ok {
-		log.Printf("Invalid data structure for K8S projects: items field missing or not an array")
 		return
 	}
 
@@ -1861,6 +4214,7 @@ func (e *Exporter) processK8SProjects(k8sProjectsData map[string]interface{}, ch
 
 				serviceName, _ := serviceItem["name"].(string)
 				regionId, _ := serviceItem["regionId"].(string)
+				e.observeRegion(regionId, serviceItem["regionName"])
 
 				// Process quota
 				if quota, ok := serviceItem["quota"].([]interface{}); ok {
@@ -1875,38 +4229,17 @@ func (e *Exporter) processK8SProjects(k8sProjectsData map[string]interface{}, ch
 							continue
 						}
 
-						// Set limit metric
+						// Service and key are labels, as they may contain characters invalid in metric names
 						if limit, ok := quotaItem["limit"].(float64); ok {
-							name := fmt.Sprintf("pskz_k8s_project_quota_%s_%s_limit", serviceName, key)
-							desc := prometheus.NewDesc(
-								name,
-								fmt.Sprintf("Quota limit for %s %s", serviceName, key),
-								[]string{"project_id", "project_name", "region_id"},
-								nil,
-							)
-							ch <- prometheus.MustNewConstMetric(
-								desc,
-								prometheus.GaugeValue,
-								limit,
-								projectId, projectName, regionId,
-							)
+							e.k8sProjectQuotaLimitMetric.WithLabelValues(
+								projectId, projectName, regionId, serviceName, key,
+							).Set(limit)
 						}
 
-						// Set usage metric
 						if inUse, ok := quotaItem["inUse"].(float64); ok {
-							name := fmt.Sprintf("pskz_k8s_project_quota_%s_%s_used", serviceName, key)
-							desc := prometheus.NewDesc(
-								name,
-								fmt.Sprintf("Quota usage for %s %s", serviceName, key),
-								[]string{"project_id", "project_name", "region_id"},
-								nil,
-							)
-							ch <- prometheus.MustNewConstMetric(
-								desc,
-								prometheus.GaugeValue,
-								inUse,
-								projectId, projectName, regionId,
-							)
+							e.k8sProjectQuotaUsedMetric.WithLabelValues(
+								projectId, projectName, regionId, serviceName, key,
+							).Set(inUse)
 						}
 					}
 				}
@@ -1916,15 +4249,8 @@ func (e *Exporter) processK8SProjects(k8sProjectsData map[string]interface{}, ch
 
 	// Set metrics for project counts by status
 	for status, count := range statusCounts {
-		name := "pskz_k8s_project_status_count"
-		desc := prometheus.NewDesc(
-			name,
-			"Number of Kubernetes projects by status",
-			[]string{"status"},
-			nil,
-		)
 		ch <- prometheus.MustNewConstMetric(
-			desc,
+			e.k8sProjectStatusDesc,
 			prometheus.GaugeValue,
 			float64(count),
 			status,
@@ -1933,15 +4259,8 @@ func (e *Exporter) processK8SProjects(k8sProjectsData map[string]interface{}, ch
 
 	// Set metrics for project counts by type
 	for projectType, count := range typesCounts {
-		name := "pskz_k8s_project_type_count"
-		desc := prometheus.NewDesc(
-			name,
-			"Number of Kubernetes projects by type",
-			[]string{"type"},
-			nil,
-		)
 		ch <- prometheus.MustNewConstMetric(
-			desc,
+			e.k8sProjectTypeDesc,
 			prometheus.GaugeValue,
 			float64(count),
 			projectType,
@@ -0,0 +1,555 @@
+package collector
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/atlet99/pscloud-exporter/internal/client"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// redirectTransport sends every request to target, whatever endpoint it was made for
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	req.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// stubAPI is a test server standing in for the PS.KZ GraphQL services. Services
+// listed in failing answer with a GraphQL error, all others with responses.
+// Responses starting with "<" are served as HTML.
+type stubAPI struct {
+	mutex     sync.Mutex
+	failing   map[string]bool
+	responses map[string]string // response body by service, {"data":{}} if missing
+	requests  map[string]int
+	header    http.Header // added to every response
+	gate      func()      // called before each response if set, e.g. to hold requests back
+}
+
+func newStubAPI(failing ...string) *stubAPI {
+	api := &stubAPI{
+		failing:   make(map[string]bool),
+		responses: make(map[string]string),
+		requests:  make(map[string]int),
+		header:    make(http.Header),
+	}
+	for _, service := range failing {
+		api.failing[service] = true
+	}
+	return api
+}
+
+func (a *stubAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	service := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/"), "/graphql")
+
+	a.mutex.Lock()
+	a.requests[service]++
+	failing := a.failing[service]
+	response, ok := a.responses[service]
+	gate := a.gate
+	for name, values := range a.header {
+		w.Header()[name] = values
+	}
+	a.mutex.Unlock()
+
+	if gate != nil {
+		gate()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if strings.HasPrefix(response, "<") {
+		w.Header().Set("Content-Type", "text/html")
+	}
+	switch {
+	case failing:
+		_, _ = w.Write([]byte(`{"errors":[{"message":"internal server error"}]}`))
+	case ok:
+		_, _ = w.Write([]byte(response))
+	default:
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}
+}
+
+// totalRequests returns the number of requests all services received
+func (a *stubAPI) totalRequests() int {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	total := 0
+	for _, count := range a.requests {
+		total += count
+	}
+	return total
+}
+
+// newTestExporter returns an exporter whose client sends all requests to api
+func newTestExporter(t *testing.T, api http.Handler, options Options) *Exporter {
+	t.Helper()
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := client.NewWithOptions("test-token", client.ClientOptions{
+		Transport:               redirectTransport{target: target},
+		CircuitFailureThreshold: -1,
+	})
+	t.Cleanup(c.Close)
+	return NewWithOptions(c, options)
+}
+
+// scrape runs one collection of e and returns pskz_scrape_success
+func scrape(t *testing.T, e *Exporter) float64 {
+	t.Helper()
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(e)
+	if _, err := reg.Gather(); err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	return testutil.ToFloat64(e.scrapeSuccessMetric)
+}
+
+func TestScrapeSuccessPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		failing []string
+		want    float64
+	}{
+		{"all fetched, policy all", SuccessPolicyAll, nil, 1},
+		{"all fetched, policy any", SuccessPolicyAny, nil, 1},
+		{"balance failed, policy all", SuccessPolicyAll, []string{"account"}, 0},
+		{"balance failed, policy any", SuccessPolicyAny, []string{"account"}, 1},
+		{"domains failed, policy all", SuccessPolicyAll, []string{"domains"}, 0},
+		{"domains failed, policy any", SuccessPolicyAny, []string{"domains"}, 1},
+		{"everything failed, policy any", SuccessPolicyAny, []string{"account", "domains", "cloud", "vps", "k8saas", "lbaas"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestExporter(t, newStubAPI(tt.failing...), Options{SuccessPolicy: tt.policy})
+			if got := scrape(t, e); got != tt.want {
+				t.Errorf("pskz_scrape_success = %v, want %v (fetch errors %v)", got, tt.want, e.fetchErrors)
+			}
+		})
+	}
+}
+
+func TestValidateSuccessPolicy(t *testing.T) {
+	for _, policy := range []string{"", SuccessPolicyAll, SuccessPolicyAny} {
+		if err := ValidateSuccessPolicy(policy); err != nil {
+			t.Errorf("ValidateSuccessPolicy(%q) = %v, want nil", policy, err)
+		}
+	}
+	if err := ValidateSuccessPolicy("most"); err == nil {
+		t.Error(`ValidateSuccessPolicy("most") = nil, want an error`)
+	}
+}
+
+func TestK8SProjectQuotaServiceNames(t *testing.T) {
+	api := newStubAPI()
+	api.responses["k8saas"] = `{"data":{"k8saas":{"project":{"pagination":{"items":[{
+		"projectId": "p1",
+		"projectName": "main",
+		"status": "ACTIVE",
+		"type": "standard",
+		"openstackServices": [
+			{"name": "block-storage", "regionId": "kz-ala-1", "quota": [{"key": "gigabytes", "limit": 500, "inUse": 120}]},
+			{"name": "object storage", "regionId": "kz-ala-1", "quota": [{"key": "max-buckets", "limit": 10, "inUse": 2}]}
+		]
+	}]}}}}}`
+	e := newTestExporter(t, api, Options{})
+	scrape(t, e)
+
+	tests := []struct {
+		service, key string
+		limit, used  float64
+	}{
+		{"block-storage", "gigabytes", 500, 120},
+		{"object storage", "max-buckets", 10, 2},
+	}
+	for _, tt := range tests {
+		labels := []string{"p1", "main", "kz-ala-1", tt.service, tt.key}
+		if got := testutil.ToFloat64(e.k8sProjectQuotaLimitMetric.WithLabelValues(labels...)); got != tt.limit {
+			t.Errorf("quota limit of %s/%s = %v, want %v", tt.service, tt.key, got, tt.limit)
+		}
+		if got := testutil.ToFloat64(e.k8sProjectQuotaUsedMetric.WithLabelValues(labels...)); got != tt.used {
+			t.Errorf("quota usage of %s/%s = %v, want %v", tt.service, tt.key, got, tt.used)
+		}
+	}
+}
+
+func TestRateLimitMetrics(t *testing.T) {
+	api := newStubAPI()
+	api.header.Set("X-RateLimit-Remaining", "42")
+	api.header.Set("X-RateLimit-Reset", "2000000000")
+	e := newTestExporter(t, api, Options{Only: "balance"})
+	scrape(t, e)
+
+	if got := testutil.ToFloat64(e.rateLimitRemainingMetric.WithLabelValues("account")); got != 42 {
+		t.Errorf("pskz_api_ratelimit_remaining{endpoint=\"account\"} = %v, want 42", got)
+	}
+	if got := testutil.ToFloat64(e.rateLimitResetMetric.WithLabelValues("account")); got != 2000000000 {
+		t.Errorf("pskz_api_ratelimit_reset_timestamp_seconds{endpoint=\"account\"} = %v, want 2000000000", got)
+	}
+}
+
+func TestRateLimitMetricsWithoutHeaders(t *testing.T) {
+	e := newTestExporter(t, newStubAPI(), Options{})
+	scrape(t, e)
+
+	if got := testutil.CollectAndCount(e.rateLimitRemainingMetric); got != 0 {
+		t.Errorf("got %d rate limit remaining series without rate limit headers, want 0", got)
+	}
+	if got := testutil.CollectAndCount(e.rateLimitResetMetric); got != 0 {
+		t.Errorf("got %d rate limit reset series without rate limit headers, want 0", got)
+	}
+}
+
+// monitoredAPI answers the single resource queries of the monitored resources,
+// reporting every resource whose request does not contain one of found as missing
+func monitoredAPI(t *testing.T, found ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		for _, match := range found {
+			if strings.Contains(string(body), match) {
+				_, _ = w.Write([]byte(`{"data":{"vpc":{"instance":{"get":{"instanceName":"web-1","status":"ACTIVE"}}},"vps":{"server":{"get":{"serverId":1,"status":"ACTIVE"}}}}}`))
+				return
+			}
+		}
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}
+}
+
+func TestMonitoredResourceFetchErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		options    Options
+		found      []string
+		kind       string
+		errorType  string
+		wantErrors map[string]float64
+		wantGroup  float64
+	}{
+		{
+			"some cloud instances failed",
+			Options{CloudInstances: []string{"i-1", "i-2"}, Only: "cloud_instances"},
+			[]string{`"i-1"`},
+			"cloud_instance", "cloud_instances_fetch_error",
+			map[string]float64{"i-1": 0, "i-2": 1}, 0,
+		},
+		{
+			"all cloud instances failed",
+			Options{CloudInstances: []string{"i-1", "i-2"}, Only: "cloud_instances"},
+			nil,
+			"cloud_instance", "cloud_instances_fetch_error",
+			map[string]float64{"i-1": 1, "i-2": 1}, 1,
+		},
+		{
+			"some VPS servers failed",
+			Options{VPSServers: []client.VPSServerRef{{ServerID: 1, RegionID: "kz-ala-1"}, {ServerID: 2, RegionID: "kz-ala-1"}}, Only: "vps_servers"},
+			[]string{`"serverId":1}`},
+			"vps_server", "vps_servers_fetch_error",
+			map[string]float64{"1": 0, "2": 1}, 0,
+		},
+		{
+			"all VPS servers failed",
+			Options{VPSServers: []client.VPSServerRef{{ServerID: 1, RegionID: "kz-ala-1"}, {ServerID: 2, RegionID: "kz-ala-1"}}, Only: "vps_servers"},
+			nil,
+			"vps_server", "vps_servers_fetch_error",
+			map[string]float64{"1": 1, "2": 1}, 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestExporter(t, monitoredAPI(t, tt.found...), tt.options)
+			scrape(t, e)
+
+			for id, want := range tt.wantErrors {
+				if got := testutil.ToFloat64(e.monitoredErrorMetric.WithLabelValues(tt.kind, id)); got != want {
+					t.Errorf("pskz_monitored_resource_fetch_error{kind=%q,id=%q} = %v, want %v", tt.kind, id, got, want)
+				}
+			}
+			if got := testutil.ToFloat64(e.lastScrapeErrorMetric.WithLabelValues(tt.errorType)); got != tt.wantGroup {
+				t.Errorf("pskz_last_scrape_error{error_type=%q} = %v, want %v", tt.errorType, got, tt.wantGroup)
+			}
+			// Failures of single resources are not data sources of their own
+			if got := testutil.ToFloat64(e.scrapeErrorsMetric); got != tt.wantGroup {
+				t.Errorf("pskz_scrape_errors_count = %v, want %v", got, tt.wantGroup)
+			}
+		})
+	}
+}
+
+// rejectingAPI answers every query with response, unless the query selects
+// field, in which case the API rejects the field
+func rejectingAPI(t *testing.T, field, response string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(string(body), field) {
+			_, _ = w.Write([]byte(`{"errors":[{"message":"Cannot query field \"` + field + `\"."}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(response))
+	}
+}
+
+func TestDomainsWithoutDNSFields(t *testing.T) {
+	domains := `{"data":{"domains":{"items":[{"name":"example.kz","status":"active","expiryDate":"2099-01-02"}]}}}`
+	e := newTestExporter(t, rejectingAPI(t, "nameservers", domains), Options{Only: "domains"})
+	scrape(t, e)
+
+	if got := testutil.ToFloat64(e.lastScrapeErrorMetric.WithLabelValues("domains_fetch_error")); got != 0 {
+		t.Errorf("pskz_last_scrape_error{error_type=\"domains_fetch_error\"} = %v, want 0", got)
+	}
+	if got := testutil.CollectAndCount(e.domainExpiryMetric); got != 1 {
+		t.Errorf("got %d domain expiry series, want 1", got)
+	}
+	for name, metric := range map[string]*prometheus.GaugeVec{
+		"pskz_domain_dnssec_enabled":   e.domainDNSSECMetric,
+		"pskz_domain_nameserver_count": e.domainNSCountMetric,
+	} {
+		if got := testutil.CollectAndCount(metric); got != 0 {
+			t.Errorf("got %d %s series without the field, want 0", got, name)
+		}
+	}
+}
+
+func TestDomainsWithoutAutoRenew(t *testing.T) {
+	domains := `{"data":{"domains":{"items":[{"name":"example.kz","status":"active","expiryDate":"2099-01-02"}]}}}`
+	e := newTestExporter(t, rejectingAPI(t, "autoRenew", domains), Options{Only: "domains", DomainAutoRenew: true})
+	scrape(t, e)
+
+	if got := testutil.ToFloat64(e.lastScrapeErrorMetric.WithLabelValues("domains_fetch_error")); got != 0 {
+		t.Errorf("pskz_last_scrape_error{error_type=\"domains_fetch_error\"} = %v, want 0", got)
+	}
+	if got := testutil.CollectAndCount(e.domainExpiryMetric); got != 1 {
+		t.Errorf("got %d domain expiry series, want 1", got)
+	}
+	if got := testutil.CollectAndCount(e.domainAutoRenewMetric); got != 0 {
+		t.Errorf("got %d auto-renew series without the field, want 0", got)
+	}
+}
+
+func TestLoadBalancersWithoutMemberHealth(t *testing.T) {
+	loadBalancers := `{"data":{"lbaas":{"loadBalancer":{"pagination":{"count":1,"items":[{
+		"_id": "lb-1",
+		"name": "web",
+		"regionId": "kz-ala-1",
+		"provisioningStatus": "ACTIVE",
+		"members": [{"_id": "m-1"}, {"_id": "m-2"}]
+	}]}}}}}`
+	e := newTestExporter(t, rejectingAPI(t, "operatingStatus", loadBalancers), Options{Only: "lbaas"})
+	scrape(t, e)
+
+	if got := testutil.ToFloat64(e.lastScrapeErrorMetric.WithLabelValues("lbaas_loadbalancers_fetch_error")); got != 0 {
+		t.Errorf("pskz_last_scrape_error{error_type=\"lbaas_loadbalancers_fetch_error\"} = %v, want 0", got)
+	}
+	if got := testutil.ToFloat64(e.lbaasMembersCountMetric.WithLabelValues("lb-1", "web")); got != 2 {
+		t.Errorf("pskz_lbaas_members_count = %v, want 2", got)
+	}
+	if got := testutil.CollectAndCount(e.lbaasMemberHealthMetric); got != 0 {
+		t.Errorf("got %d member health series without the field, want 0", got)
+	}
+	// Without member health a load balancer is not reported as unhealthy
+	if got := testutil.ToFloat64(e.lbaasUnhealthyMetric.WithLabelValues("lb-1", "web")); got != 0 {
+		t.Errorf("pskz_lbaas_unhealthy = %v, want 0", got)
+	}
+}
+
+func TestK8SClustersWithoutHealth(t *testing.T) {
+	clusters := `{"data":{"k8saas":{"cluster":{"pagination":{"count":1,"items":[{
+		"_id": "c-1",
+		"name": "prod",
+		"status": "ACTIVE",
+		"nodeCount": 3,
+		"masterCount": 1
+	}]}}}}}`
+	e := newTestExporter(t, rejectingAPI(t, "healthStatus", clusters), Options{Only: "k8s_clusters"})
+	scrape(t, e)
+
+	if got := testutil.ToFloat64(e.lastScrapeErrorMetric.WithLabelValues("k8s_clusters_fetch_error")); got != 0 {
+		t.Errorf("pskz_last_scrape_error{error_type=\"k8s_clusters_fetch_error\"} = %v, want 0", got)
+	}
+	if got := testutil.ToFloat64(e.k8sClusterNodesMetric.WithLabelValues("c-1", "prod")); got != 3 {
+		t.Errorf("cluster nodes = %v, want 3", got)
+	}
+	if got := testutil.CollectAndCount(e.k8sClusterAPIHealthyMetric); got != 0 {
+		t.Errorf("got %d cluster health series without the field, want 0", got)
+	}
+}
+
+func TestLoadBalancersWithoutTimestamps(t *testing.T) {
+	loadBalancers := `{"data":{"lbaas":{"loadBalancer":{"pagination":{"count":1,"items":[{
+		"_id": "lb-1",
+		"name": "web",
+		"regionId": "kz-ala-1",
+		"provisioningStatus": "PENDING_UPDATE"
+	}]}}}}}`
+	e := newTestExporter(t, rejectingAPI(t, "updatedAt", loadBalancers), Options{Only: "lbaas"})
+	scrape(t, e)
+
+	if got := testutil.ToFloat64(e.lastScrapeErrorMetric.WithLabelValues("lbaas_loadbalancers_fetch_error")); got != 0 {
+		t.Errorf("pskz_last_scrape_error{error_type=\"lbaas_loadbalancers_fetch_error\"} = %v, want 0", got)
+	}
+	if got := testutil.ToFloat64(e.lbaasLoadBalancerCountMetric.WithLabelValues("PENDING_UPDATE")); got != 1 {
+		t.Errorf("load balancers in PENDING_UPDATE = %v, want 1", got)
+	}
+	if got := testutil.CollectAndCount(e.lbaasProvisioningAgeMetric); got != 0 {
+		t.Errorf("got %d provisioning age series without timestamps, want 0", got)
+	}
+}
+
+func TestDNSRecordsHTMLResponse(t *testing.T) {
+	api := newStubAPI()
+	api.responses["domains"] = "<html><body>Maintenance</body></html>"
+	e := newTestExporter(t, api, Options{DNSZones: []string{"example.kz"}, Only: "dns_records"})
+	scrape(t, e)
+
+	for _, errorType := range []string{"dns_records_fetch_error", htmlResponseError} {
+		if got := testutil.ToFloat64(e.lastScrapeErrorMetric.WithLabelValues(errorType)); got != 1 {
+			t.Errorf("pskz_last_scrape_error{error_type=%q} = %v, want 1", errorType, got)
+		}
+	}
+}
+
+func TestCloudInstancesInTransition(t *testing.T) {
+	api := newStubAPI()
+	api.responses["cloud"] = `{"data":{"vpc":{"instance":{"pagination":{"items":[
+		{"instanceName": "web-1", "status": "BUILD"},
+		{"instanceName": "web-2", "status": "RESIZE"},
+		{"instanceName": "web-3", "status": "RESIZE"},
+		{"instanceName": "db-1", "status": "MIGRATING"},
+		{"instanceName": "db-2", "status": "HARD_REBOOT"},
+		{"instanceName": "app-1", "status": "ACTIVE"},
+		{"instanceName": "app-2", "status": "ERROR"}
+	]}}}}}`
+	e := newTestExporter(t, api, Options{Only: "cloud_instances"})
+	scrape(t, e)
+
+	want := map[string]float64{
+		"BUILD":         1,
+		"RESIZE":        2,
+		"MIGRATING":     1,
+		"HARD_REBOOT":   1,
+		"REBOOT":        0,
+		"REBUILD":       0,
+		"VERIFY_RESIZE": 0,
+		"REVERT_RESIZE": 0,
+	}
+	if got := testutil.CollectAndCount(e.cloudTransitionMetric); got != len(want) {
+		t.Errorf("got %d transitional states, want %d", got, len(want))
+	}
+	for status, count := range want {
+		if got := testutil.ToFloat64(e.cloudTransitionMetric.WithLabelValues(status)); got != count {
+			t.Errorf("instances in %s = %v, want %v", status, got, count)
+		}
+	}
+
+	// Every instance keeps its raw status, including states that are not transitional
+	for instance, status := range map[string]string{"web-2": "RESIZE", "app-1": "ACTIVE", "app-2": "ERROR"} {
+		if got := testutil.ToFloat64(e.cloudInstanceStatusMetric.WithLabelValues(instance, status)); got != 1 {
+			t.Errorf("pskz_cloud_instance_status{instance_name=%q,status=%q} = %v, want 1", instance, status, got)
+		}
+	}
+}
+
+func TestConcurrentScrapesShareCollection(t *testing.T) {
+	// The number of requests of a single scrape
+	baseline := newStubAPI()
+	scrape(t, newTestExporter(t, baseline, Options{}))
+	perScrape := baseline.totalRequests()
+	if perScrape == 0 {
+		t.Fatal("a scrape sent no requests")
+	}
+
+	// Requests are held back until all scrapes have started
+	api := newStubAPI()
+	arrived, release := make(chan struct{}), make(chan struct{})
+	var first sync.Once
+	api.gate = func() {
+		first.Do(func() { close(arrived) })
+		<-release
+	}
+	e := newTestExporter(t, api, Options{})
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(e)
+
+	const scrapes = 5
+	var wg sync.WaitGroup
+	for i := 0; i < scrapes; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := reg.Gather(); err != nil {
+				t.Errorf("Gather() error = %v", err)
+			}
+		}()
+	}
+
+	<-arrived
+	// Give the other scrapes time to join the collection in flight
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := api.totalRequests(); got != perScrape {
+		t.Errorf("%d concurrent scrapes sent %d requests, want %d (one collection)", scrapes, got, perScrape)
+	}
+	if got := testutil.ToFloat64(e.scrapeSharedMetric); got != scrapes-1 {
+		t.Errorf("pskz_scrape_shared_total = %v, want %d", got, scrapes-1)
+	}
+}
+
+func TestParseExpiryDate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2030-01-02", time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"2030-01-02T15:04:05Z", time.Date(2030, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{"2030-01-02T15:04:05+06:00", time.Date(2030, 1, 2, 9, 4, 5, 0, time.UTC)},
+		{"2030-01-02 15:04:05", time.Date(2030, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{"1893456000", time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseExpiryDate(tt.value)
+		if err != nil {
+			t.Errorf("parseExpiryDate(%q) error = %v", tt.value, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseExpiryDate(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+
+	for _, value := range []string{"02.01.2030", "", "soon"} {
+		if got, err := parseExpiryDate(value); err == nil {
+			t.Errorf("parseExpiryDate(%q) = %v, want an error", value, got)
+		}
+	}
+}
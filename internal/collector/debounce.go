@@ -0,0 +1,139 @@
+package collector
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// descNameRe extracts the fully-qualified metric name from a Desc string
+var descNameRe = regexp.MustCompile(`fqName: "([^"]+)"`)
+
+// MetricName returns the fully-qualified name of the metric described by desc
+func MetricName(desc *prometheus.Desc) string {
+	if match := descNameRe.FindStringSubmatch(desc.String()); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// debounceState holds the published and candidate values of a single series
+type debounceState struct {
+	stable    float64
+	candidate float64
+	count     int
+	seen      bool
+}
+
+// debouncer delays value changes of selected metric families until the
+// new value has been observed in a number of consecutive scrapes
+type debouncer struct {
+	scrapes map[string]int // metric family name -> consecutive scrapes required
+	series  map[string]*debounceState
+}
+
+// newDebouncer creates a debouncer for the given metric families.
+// It returns nil when no family needs debouncing.
+func newDebouncer(scrapes map[string]int) *debouncer {
+	enabled := make(map[string]int)
+	for name, n := range scrapes {
+		if n > 1 {
+			enabled[name] = n
+		}
+	}
+	if len(enabled) == 0 {
+		return nil
+	}
+
+	return &debouncer{
+		scrapes: enabled,
+		series:  make(map[string]*debounceState),
+	}
+}
+
+// apply returns the metric to expose for m, holding back value changes
+// that have not yet been seen for enough consecutive scrapes
+func (d *debouncer) apply(m prometheus.Metric) prometheus.Metric {
+	name := MetricName(m.Desc())
+	required, ok := d.scrapes[name]
+	if !ok {
+		return m
+	}
+
+	var pb dto.Metric
+	if err := m.Write(&pb); err != nil || pb.Gauge == nil {
+		return m
+	}
+	value := pb.GetGauge().GetValue()
+
+	key := seriesKey(name, pb.GetLabel())
+	state, ok := d.series[key]
+	if !ok {
+		d.series[key] = &debounceState{stable: value, seen: true}
+		return m
+	}
+	state.seen = true
+
+	switch {
+	case value == state.stable:
+		state.count = 0
+		return m
+	case value == state.candidate && state.count > 0:
+		state.count++
+	default:
+		state.candidate = value
+		state.count = 1
+	}
+
+	if state.count >= required {
+		state.stable = value
+		state.count = 0
+		return m
+	}
+
+	return &debouncedMetric{Metric: m, value: state.stable}
+}
+
+// begin marks the start of a scrape
+func (d *debouncer) begin() {
+	for _, state := range d.series {
+		state.seen = false
+	}
+}
+
+// end drops the state of series that disappeared during the scrape
+func (d *debouncer) end() {
+	for key, state := range d.series {
+		if !state.seen {
+			delete(d.series, key)
+		}
+	}
+}
+
+// seriesKey builds a unique key for a series from its name and labels
+func seriesKey(name string, labels []*dto.LabelPair) string {
+	pairs := make([]string, 0, len(labels))
+	for _, label := range labels {
+		pairs = append(pairs, label.GetName()+"="+label.GetValue())
+	}
+	sort.Strings(pairs)
+	return name + "{" + strings.Join(pairs, ",") + "}"
+}
+
+// debouncedMetric exposes a gauge with its previously published value
+type debouncedMetric struct {
+	prometheus.Metric
+	value float64
+}
+
+// Write implements prometheus.Metric
+func (m *debouncedMetric) Write(out *dto.Metric) error {
+	if err := m.Metric.Write(out); err != nil {
+		return err
+	}
+	out.Gauge.Value = &m.value
+	return nil
+}
@@ -0,0 +1,138 @@
+package collector
+
+import (
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"time"
+
+	"github.com/atlet99/pscloud-exporter/internal/client"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// deltaGroups returns the metrics filled from each data group's API response.
+// Metrics of these groups are reset only when their group is processed, which
+// lets delta exposition keep the series of unchanged groups between scrapes.
+func (e *Exporter) deltaGroups() map[string][]*prometheus.GaugeVec {
+	return map[string][]*prometheus.GaugeVec{
+		"projects": {
+			e.projectAmountMetric,
+			e.projectDiskUsageMetric,
+			e.projectDiskLimitMetric,
+			e.projectBwUsageMetric,
+			e.projectBwLimitMetric,
+			e.projectCountMetric,
+			e.servicesCountMetric,
+		},
+		"invoices": {
+			e.invoiceCountersMetric,
+			e.invoiceAmountMetric,
+		},
+		"k8s_clusters": {
+			e.k8sClusterCountMetric,
+			e.k8sClusterStatusMetric,
+			e.k8sClusterNodesMetric,
+			e.k8sClusterMastersMetric,
+			e.k8sClusterAPIHealthyMetric,
+			e.k8sNodeGroupStatusMetric,
+			e.k8sNodeGroupNodesMetric,
+			e.k8sNodeGroupReadyNodesMetric,
+			e.k8sNodeGroupCoresMetric,
+			e.k8sNodeGroupRAMMetric,
+			e.k8sNodeGroupDiskMetric,
+			e.k8sTemplateCoresMetric,
+			e.k8sTemplateRAMMetric,
+		},
+		"lbaas": {
+			e.lbaasLoadBalancerCountMetric,
+			e.lbaasLoadBalancerStatusMetric,
+			e.lbaasListenersCountMetric,
+			e.lbaasPoolsCountMetric,
+			e.lbaasMembersCountMetric,
+			e.lbaasListenersAccountMetric,
+			e.lbaasPoolsAccountMetric,
+			e.lbaasMembersAccountMetric,
+			e.lbaasMemberHealthMetric,
+			e.lbaasUnhealthyMetric,
+			e.lbaasFlavorMetric,
+			e.lbaasFloatingIPMetric,
+		},
+	}
+}
+
+// fingerprint hashes the JSON encoding of an API response.
+// ok is false if the data cannot be encoded.
+func fingerprint(data interface{}) (uint64, bool) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return 0, false
+	}
+	h := fnv.New64a()
+	_, _ = h.Write(encoded)
+	return h.Sum64(), true
+}
+
+// processGroup resets the metrics of a data group and runs process to fill them.
+// With delta exposition enabled, a response identical to the previous scrape's
+// keeps the existing series and regions of the group and skips process.
+func (e *Exporter) processGroup(group string, data interface{}, process func()) {
+	sum, ok := fingerprint(data)
+	if e.deltaExposition && ok {
+		if last, seen := e.groupFingerprints[group]; seen && last == sum {
+			e.setGroupFetched(group, time.Now())
+			e.mergeRegions(e.groupRegions[group])
+			e.groupsServed["cached"]++
+			return
+		}
+	}
+	e.groupsServed["fresh"]++
+	e.setGroupFetched(group, time.Now())
+
+	for _, metric := range e.groupMetrics[group] {
+		metric.Reset()
+	}
+
+	// Track the regions of this group separately so they can be reused when it is skipped
+	regions := e.regions
+	e.regions = make(map[string]string)
+	process()
+	groupRegions := e.regions
+	e.regions = regions
+	e.mergeRegions(groupRegions)
+
+	if ok {
+		e.groupFingerprints[group] = sum
+		e.groupRegions[group] = groupRegions
+	} else {
+		delete(e.groupFingerprints, group)
+	}
+}
+
+// resetGroup clears the series of a data group whose data could not be fetched
+// and reports whether it did. While the circuit breaker of the group's endpoint
+// is open, the series of the previous scrape are kept instead.
+func (e *Exporter) resetGroup(group string, err error) bool {
+	if errors.Is(err, client.ErrCircuitOpen) {
+		e.mergeRegions(e.groupRegions[group])
+		e.groupsServed["cached"]++
+		return false
+	}
+
+	for _, metric := range e.groupMetrics[group] {
+		metric.Reset()
+	}
+	delete(e.groupFingerprints, group)
+	delete(e.groupRegions, group)
+	e.setGroupFetched(group, time.Time{})
+	return true
+}
+
+// mergeRegions adds regions to the current scrape, keeping display names over bare IDs
+func (e *Exporter) mergeRegions(regions map[string]string) {
+	for regionID, name := range regions {
+		if existing, seen := e.regions[regionID]; seen && existing != regionID {
+			continue
+		}
+		e.regions[regionID] = name
+	}
+}
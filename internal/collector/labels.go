@@ -0,0 +1,29 @@
+package collector
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// sanitizeLabelValue replaces invalid UTF-8 byte sequences in value with the
+// Unicode replacement character. Prometheus rejects label values that are not
+// valid UTF-8 and WithLabelValues panics on them, failing the whole scrape.
+//
+// Strings decoded from API responses are already valid, as encoding/json
+// replaces invalid sequences itself; values from other sources such as the
+// configuration, environment variables or build flags must pass through here.
+func sanitizeLabelValue(value string) string {
+	if utf8.ValidString(value) {
+		return value
+	}
+	return strings.ToValidUTF8(value, "\uFFFD")
+}
+
+// sanitizeLabelValues applies sanitizeLabelValue to each of values
+func sanitizeLabelValues(values ...string) []string {
+	sanitized := make([]string, len(values))
+	for i, value := range values {
+		sanitized[i] = sanitizeLabelValue(value)
+	}
+	return sanitized
+}
@@ -0,0 +1,77 @@
+package collector
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/atlet99/pscloud-exporter/internal/client"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestSanitizeLabelValue(t *testing.T) {
+	tests := []struct {
+		value, want string
+	}{
+		{"example.kz", "example.kz"},
+		{"Алматы", "Алматы"},
+		{"", ""},
+		{"a\xffb", "a\uFFFDb"},
+		{"a\xff\xfeb", "a\uFFFDb"},
+		{"\xc3", "\uFFFD"},
+	}
+	for _, tt := range tests {
+		if got := sanitizeLabelValue(tt.value); got != tt.want {
+			t.Errorf("sanitizeLabelValue(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestSanitizeLabelValues(t *testing.T) {
+	got := sanitizeLabelValues("v1.0\xff", "ok")
+	if len(got) != 2 || got[0] != "v1.0\uFFFD" || got[1] != "ok" {
+		t.Errorf("sanitizeLabelValues() = %q", got)
+	}
+}
+
+// TestInvalidUTF8BuildInfo checks that invalid build flags do not break the exporter
+func TestInvalidUTF8BuildInfo(t *testing.T) {
+	e := NewWithOptions(client.New("test-token"), Options{Version: "v1.0\xff", Build: "\xfe"})
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(e.exporterInfoMetric)
+	if _, err := reg.Gather(); err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+}
+
+// TestInvalidUTF8ConfigLabels checks that invalid configured values used as labels do not break the exporter
+func TestInvalidUTF8ConfigLabels(t *testing.T) {
+	api := newStubAPI()
+	api.responses["domains"] = `{"data":{"domains":{"dns":{"records":{"count":1,"items":[
+		{"type":"A","name":"www","value":"192.0.2.1","ttl":300}
+	]}}}}}`
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := client.NewWithOptions("test-token", client.ClientOptions{
+		BaseURL:                 "https://console.ps.kz/\xff",
+		Transport:               redirectTransport{target: target},
+		CircuitFailureThreshold: -1,
+	})
+	t.Cleanup(c.Close)
+	e := NewWithOptions(c, Options{DNSZones: []string{"example\xff.kz"}, Only: "dns_records"})
+	scrape(t, e)
+
+	if got := testutil.ToFloat64(e.targetInfoMetric.WithLabelValues("https://console.ps.kz/\uFFFD")); got != 1 {
+		t.Errorf("pskz_target_info with the sanitized base URL = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(e.dnsRecordTTLMetric.WithLabelValues("example\uFFFD.kz", "A", "www", "192.0.2.1")); got != 300 {
+		t.Errorf("DNS record TTL in the sanitized zone = %v, want 300", got)
+	}
+}
@@ -0,0 +1,96 @@
+package collector
+
+import (
+	"fmt"
+	"strings"
+)
+
+// metricNamespace is the prefix every metric of this exporter must carry
+const metricNamespace = "pskz_"
+
+// metricUnitSuffixes are the recognized unit and type suffixes of metric names
+var metricUnitSuffixes = []string{
+	"_seconds",
+	"_days",
+	"_bytes",
+	"_gb",
+	"_mb",
+	"_ratio",
+	"_total",
+	"_count",
+	"_info",
+}
+
+// unitlessMetrics are metrics that predate the naming rules and are kept
+// under their original names for compatibility. This list must not grow.
+var unitlessMetrics = map[string]bool{
+	"pskz_api_ratelimit_remaining":   true,
+	"pskz_blocked_balance":           true,
+	"pskz_bonus_balance":             true,
+	"pskz_cloud_quota":               true,
+	"pskz_cloud_summary":             true,
+	"pskz_credit_balance":            true,
+	"pskz_debt_balance":              true,
+	"pskz_domain_autorenew_enabled":  true,
+	"pskz_domain_counters":           true,
+	"pskz_domain_dnssec_enabled":     true,
+	"pskz_domain_price":              true,
+	"pskz_domain_status":             true,
+	"pskz_invoice_amount":            true,
+	"pskz_invoice_counters":          true,
+	"pskz_k8s_cluster_api_healthy":   true,
+	"pskz_k8s_cluster_masters":       true,
+	"pskz_k8s_cluster_nodes":         true,
+	"pskz_k8s_cluster_status":        true,
+	"pskz_k8s_nodegroup_cores":       true,
+	"pskz_k8s_nodegroup_nodes":       true,
+	"pskz_k8s_nodegroup_status":      true,
+	"pskz_last_scrape_error":         true,
+	"pskz_lbaas_flavor":              true,
+	"pskz_lbaas_floating_ip":         true,
+	"pskz_lbaas_loadbalancer_status": true,
+	"pskz_lbaas_member_health":       true,
+	"pskz_prepay_balance":            true,
+	"pskz_project_amount":            true,
+	"pskz_scrape_success":            true,
+	"pskz_server_cores":              true,
+	"pskz_server_status":             true,
+	"pskz_vps_backup_status":         true,
+	"pskz_vps_has_backup":            true,
+	"pskz_vps_server_amount":         true,
+	"pskz_vps_server_cores":          true,
+	"pskz_vps_server_status":         true,
+}
+
+// dimensionlessMetrics are newer metrics without a unit because their value
+// has none: flags and states (0 or 1), money amounts and quota values whose
+// unit depends on a label. Anything measured in a unit must carry its suffix.
+var dimensionlessMetrics = map[string]bool{
+	"pskz_circuit_open":                     true,
+	"pskz_cloud_instance_status":            true,
+	"pskz_credit_overdue":                   true,
+	"pskz_domain_privacy_enabled":           true,
+	"pskz_domain_zone_price_reg_renew_diff": true,
+	"pskz_k8s_project_quota_limit":          true,
+	"pskz_k8s_project_quota_used":           true,
+	"pskz_lbaas_unhealthy":                  true,
+	"pskz_monitored_resource_fetch_error":   true,
+	"pskz_prepay_balance_change":            true,
+	"pskz_schema_field_missing":             true,
+}
+
+// checkMetricName reports whether name carries the namespace and a recognized unit suffix
+func checkMetricName(name string) error {
+	if !strings.HasPrefix(name, metricNamespace) {
+		return fmt.Errorf("metric %q is missing the %q namespace", name, metricNamespace)
+	}
+	if unitlessMetrics[name] || dimensionlessMetrics[name] {
+		return nil
+	}
+	for _, suffix := range metricUnitSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("metric %q has no recognized unit suffix (one of %s)", name, strings.Join(metricUnitSuffixes, ", "))
+}
@@ -0,0 +1,56 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/atlet99/pscloud-exporter/internal/client"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestCheckMetricName(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"pskz_domain_expiry_days", true},
+		{"pskz_api_request_duration_seconds", true},
+		{"pskz_prepay_balance", true},
+		{"pskz_circuit_open", true},
+		{"pskz_domain_expiry", false},
+		{"domain_expiry_days", false},
+	}
+	for _, tt := range tests {
+		if err := checkMetricName(tt.name); (err == nil) != tt.valid {
+			t.Errorf("checkMetricName(%q) = %v, want valid %v", tt.name, err, tt.valid)
+		}
+	}
+}
+
+// TestExporterMetricNames checks every metric the exporter describes against the naming rules
+func TestExporterMetricNames(t *testing.T) {
+	e := New(client.New("test-token"), "")
+
+	ch := make(chan *prometheus.Desc)
+	go func() {
+		e.Describe(ch)
+		close(ch)
+	}()
+
+	described := make(map[string]bool)
+	for desc := range ch {
+		name := MetricName(desc)
+		described[name] = true
+		if err := checkMetricName(name); err != nil {
+			t.Error(err)
+		}
+	}
+
+	// Exemptions for metrics that no longer exist would let their names be reused without a unit
+	for _, exempt := range []map[string]bool{unitlessMetrics, dimensionlessMetrics} {
+		for name := range exempt {
+			if !described[name] {
+				t.Errorf("%s is exempt from the unit suffix rule but not described by the exporter", name)
+			}
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package collector
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Relabel actions
+const (
+	// RelabelRename renames the Source label to Target
+	RelabelRename = "rename"
+	// RelabelDrop drops the series whose Source label has the value Target
+	RelabelDrop = "drop"
+	// RelabelLowercase lowercases the value of the Source label
+	RelabelLowercase = "lowercase"
+)
+
+// RelabelRule changes the labels of every series that carries the Source label
+type RelabelRule struct {
+	Source string
+	Target string
+	Action string
+}
+
+// labelNameRe matches valid Prometheus label names
+var labelNameRe = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// descHelpRe extracts the help text from a Desc string
+var descHelpRe = regexp.MustCompile(`help: ("(?:[^"\\]|\\.)*")`)
+
+// ValidateRelabelRules checks that every rule has a known action and valid label names
+func ValidateRelabelRules(rules []RelabelRule) error {
+	for i, rule := range rules {
+		if !labelNameRe.MatchString(rule.Source) {
+			return fmt.Errorf("relabel rule %d: invalid source label %q", i+1, rule.Source)
+		}
+		switch rule.Action {
+		case RelabelRename:
+			if !labelNameRe.MatchString(rule.Target) || strings.HasPrefix(rule.Target, "__") {
+				return fmt.Errorf("relabel rule %d: invalid target label %q", i+1, rule.Target)
+			}
+		case RelabelDrop, RelabelLowercase:
+		default:
+			return fmt.Errorf("relabel rule %d: invalid action %q (must be %s, %s or %s)",
+				i+1, rule.Action, RelabelRename, RelabelDrop, RelabelLowercase)
+		}
+	}
+	return nil
+}
+
+// relabeledSeries is a series whose labels were changed by relabel rules.
+// Series that end up with the same labels are combined by summing their values.
+type relabeledSeries struct {
+	name      string
+	help      string
+	valueType prometheus.ValueType
+	labels    []*dto.LabelPair
+	value     float64
+	timestamp *int64 // milliseconds, if the series carries an explicit timestamp
+}
+
+// relabeler applies relabel rules to gauges and counters before they are exposed
+type relabeler struct {
+	rules  []RelabelRule
+	series map[string]*relabeledSeries
+	order  []string
+}
+
+// newRelabeler creates a relabeler for rules. It returns nil when there are none.
+func newRelabeler(rules []RelabelRule) *relabeler {
+	if len(rules) == 0 {
+		return nil
+	}
+	return &relabeler{
+		rules:  rules,
+		series: make(map[string]*relabeledSeries),
+	}
+}
+
+// add returns m if no rule applies to it. Otherwise the relabeled series is
+// held until flush and nil is returned; dropped series are discarded.
+func (r *relabeler) add(m prometheus.Metric) prometheus.Metric {
+	var pb dto.Metric
+	if err := m.Write(&pb); err != nil {
+		return m
+	}
+
+	var valueType prometheus.ValueType
+	var value float64
+	switch {
+	case pb.Gauge != nil:
+		valueType, value = prometheus.GaugeValue, pb.GetGauge().GetValue()
+	case pb.Counter != nil:
+		valueType, value = prometheus.CounterValue, pb.GetCounter().GetValue()
+	case pb.Untyped != nil:
+		valueType, value = prometheus.UntypedValue, pb.GetUntyped().GetValue()
+	default:
+		// Histograms and summaries are exposed unchanged
+		return m
+	}
+
+	labels := make(map[string]string, len(pb.GetLabel()))
+	for _, label := range pb.GetLabel() {
+		labels[label.GetName()] = label.GetValue()
+	}
+
+	changed := false
+	for _, rule := range r.rules {
+		labelValue, ok := labels[rule.Source]
+		if !ok {
+			continue
+		}
+		changed = true
+		switch rule.Action {
+		case RelabelRename:
+			delete(labels, rule.Source)
+			labels[rule.Target] = labelValue
+		case RelabelDrop:
+			if labelValue == rule.Target {
+				return nil
+			}
+		case RelabelLowercase:
+			labels[rule.Source] = strings.ToLower(labelValue)
+		}
+	}
+	if !changed {
+		return m
+	}
+
+	pairs := make([]*dto.LabelPair, 0, len(labels))
+	for labelName, labelValue := range labels {
+		pairs = append(pairs, &dto.LabelPair{Name: &labelName, Value: &labelValue})
+	}
+
+	name := MetricName(m.Desc())
+	key := seriesKey(name, pairs)
+	if existing, ok := r.series[key]; ok {
+		existing.value += value
+		return nil
+	}
+
+	help := ""
+	if match := descHelpRe.FindStringSubmatch(m.Desc().String()); match != nil {
+		help, _ = strconv.Unquote(match[1])
+	}
+	r.series[key] = &relabeledSeries{name: name, help: help, valueType: valueType, labels: pairs, value: value, timestamp: pb.TimestampMs}
+	r.order = append(r.order, key)
+	return nil
+}
+
+// flush returns the relabeled series held since the previous flush
+func (r *relabeler) flush() []prometheus.Metric {
+	metrics := make([]prometheus.Metric, 0, len(r.order))
+	for _, key := range r.order {
+		series := r.series[key]
+		names := make([]string, 0, len(series.labels))
+		values := make([]string, 0, len(series.labels))
+		for _, label := range series.labels {
+			names = append(names, label.GetName())
+			values = append(values, label.GetValue())
+		}
+		desc := prometheus.NewDesc(series.name, series.help, names, nil)
+		m, err := prometheus.NewConstMetric(desc, series.valueType, series.value, values...)
+		if err != nil {
+			m = prometheus.NewInvalidMetric(desc, err)
+		} else if series.timestamp != nil {
+			m = prometheus.NewMetricWithTimestamp(time.UnixMilli(*series.timestamp), m)
+		}
+		metrics = append(metrics, m)
+	}
+
+	r.series = make(map[string]*relabeledSeries)
+	r.order = nil
+	return metrics
+}
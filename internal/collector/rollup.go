@@ -0,0 +1,91 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// gaugeSample is the value of one series of a gauge vector with its labels
+type gaugeSample struct {
+	labels map[string]string
+	value  float64
+}
+
+// gaugeSamples returns the current series of a gauge vector
+func gaugeSamples(vec *prometheus.GaugeVec) []gaugeSample {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		vec.Collect(ch)
+		close(ch)
+	}()
+
+	var samples []gaugeSample
+	for m := range ch {
+		var pb dto.Metric
+		if err := m.Write(&pb); err != nil || pb.Gauge == nil {
+			continue
+		}
+		sample := gaugeSample{labels: make(map[string]string), value: pb.GetGauge().GetValue()}
+		for _, label := range pb.GetLabel() {
+			sample.labels[label.GetName()] = label.GetValue()
+		}
+		samples = append(samples, sample)
+	}
+	return samples
+}
+
+// sumGauge returns the sum of all series of a gauge vector
+func sumGauge(vec *prometheus.GaugeVec) float64 {
+	var sum float64
+	for _, sample := range gaugeSamples(vec) {
+		sum += sample.value
+	}
+	return sum
+}
+
+// nodeGroupKey identifies a Kubernetes node group across the node group metrics
+func nodeGroupKey(labels map[string]string) [2]string {
+	return [2]string{labels["cluster_id"], labels["nodegroup_id"]}
+}
+
+// updateAccountTotals sums the cores, RAM and instances of the cloud, VPS and
+// Kubernetes services into account-level totals. It reads the per-resource
+// series after all data groups are gathered, so groups kept from the previous
+// scrape by delta exposition are included.
+func (e *Exporter) updateAccountTotals() {
+	var cores, ramGB, instances float64
+
+	// The cloud summary already covers all regions of the cloud service
+	for _, sample := range gaugeSamples(e.cloudSummaryMetric) {
+		switch sample.labels["resource"] {
+		case "cpu_cores":
+			cores += sample.value
+		case "ram_gb":
+			ramGB += sample.value
+		case "instances_count":
+			instances += sample.value
+		}
+	}
+
+	// VPS RAM is reported by the tariff in GB
+	cores += sumGauge(e.vpsServerCoresMetric)
+	ramGB += sumGauge(e.vpsServerRamMetric)
+	instances += sumGauge(e.vpsServerCountMetric)
+
+	// Node group flavors are per node, so they are multiplied by the node count
+	nodes := make(map[[2]string]float64)
+	for _, sample := range gaugeSamples(e.k8sNodeGroupNodesMetric) {
+		nodes[nodeGroupKey(sample.labels)] = sample.value
+		instances += sample.value
+	}
+	for _, sample := range gaugeSamples(e.k8sNodeGroupCoresMetric) {
+		cores += sample.value * nodes[nodeGroupKey(sample.labels)]
+	}
+	for _, sample := range gaugeSamples(e.k8sNodeGroupRAMMetric) {
+		ramGB += sample.value * nodes[nodeGroupKey(sample.labels)] / 1024
+	}
+
+	e.accountCoresMetric.WithLabelValues("account").Set(cores)
+	e.accountRAMMetric.WithLabelValues("account").Set(ramGB)
+	e.accountInstancesMetric.WithLabelValues("account").Set(instances)
+}
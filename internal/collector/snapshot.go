@@ -0,0 +1,37 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/proto"
+)
+
+// snapshotMetric is a metric frozen at the time it was collected, so that
+// later collections do not change what is exposed to earlier callers
+type snapshotMetric struct {
+	desc   *prometheus.Desc
+	metric *dto.Metric
+	err    error
+}
+
+// newSnapshotMetric captures the current state of m
+func newSnapshotMetric(m prometheus.Metric) prometheus.Metric {
+	pb := &dto.Metric{}
+	err := m.Write(pb)
+	return &snapshotMetric{desc: m.Desc(), metric: pb, err: err}
+}
+
+// Desc implements prometheus.Metric
+func (m *snapshotMetric) Desc() *prometheus.Desc {
+	return m.desc
+}
+
+// Write implements prometheus.Metric
+func (m *snapshotMetric) Write(out *dto.Metric) error {
+	if m.err != nil {
+		return m.err
+	}
+	proto.Reset(out)
+	proto.Merge(out, m.metric)
+	return nil
+}
@@ -0,0 +1,69 @@
+package collector
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Status is a summary of the account taken from the metrics of the latest scrape
+type Status struct {
+	LastScrape    time.Time          `json:"last_scrape"`
+	Balance       map[string]float64 `json:"balance"`
+	Domains       int                `json:"domains"`
+	ActiveServers int                `json:"active_servers"`
+	Clusters      int                `json:"clusters"`
+}
+
+// Status returns the account summary of the latest scrape without querying the API.
+// ok is false before the first scrape. It waits for a scrape in progress to finish.
+func (e *Exporter) Status() (status Status, ok bool) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.lastScrape.IsZero() {
+		return Status{}, false
+	}
+	status.LastScrape = e.lastScrape
+
+	// Balances are only present if the account balance could be fetched
+	status.Balance = make(map[string]float64)
+	balances := map[string]*prometheus.GaugeVec{
+		"prepay":  e.prepayMetric,
+		"credit":  e.creditMetric,
+		"debt":    e.debtMetric,
+		"bonus":   e.bonusMetric,
+		"blocked": e.blockedMetric,
+	}
+	for kind, metric := range balances {
+		for _, sample := range gaugeSamples(metric) {
+			status.Balance[kind] += sample.value
+		}
+	}
+
+	domains := make(map[string]bool)
+	for _, sample := range gaugeSamples(e.domainStatusMetric) {
+		domains[sample.labels["domain"]] = true
+	}
+	status.Domains = len(domains)
+
+	// The VPS status metric also carries per-status totals under server_id "all"
+	for _, sample := range gaugeSamples(e.vpsServerStatusMetric) {
+		if sample.labels["server_id"] != "all" && sample.value == 1 {
+			status.ActiveServers++
+		}
+	}
+	for _, sample := range gaugeSamples(e.serverStatusMetric) {
+		if sample.value == 1 {
+			status.ActiveServers++
+		}
+	}
+
+	for _, sample := range gaugeSamples(e.k8sClusterCountMetric) {
+		if sample.labels["status"] == "total" {
+			status.Clusters = int(sample.value)
+		}
+	}
+
+	return status, true
+}
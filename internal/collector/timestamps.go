@@ -0,0 +1,49 @@
+package collector
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// groupDescs maps the descriptors of the data group metrics to their group
+func groupDescs(groups map[string][]*prometheus.GaugeVec) map[*prometheus.Desc]string {
+	descs := make(map[*prometheus.Desc]string)
+	for group, metrics := range groups {
+		for _, metric := range metrics {
+			ch := make(chan *prometheus.Desc, 1)
+			metric.Describe(ch)
+			close(ch)
+			for desc := range ch {
+				descs[desc] = group
+			}
+		}
+	}
+	return descs
+}
+
+// setGroupFetched records when the data of a group was last fetched
+func (e *Exporter) setGroupFetched(group string, fetched time.Time) {
+	e.fetchedMutex.Lock()
+	defer e.fetchedMutex.Unlock()
+	if fetched.IsZero() {
+		delete(e.groupFetched, group)
+		return
+	}
+	e.groupFetched[group] = fetched
+}
+
+// withTimestamp attaches the time its data was fetched to m. Series of data
+// groups kept from an earlier scrape carry the time of that scrape's fetch,
+// all others the start of the current collection.
+func (e *Exporter) withTimestamp(m prometheus.Metric, collected time.Time) prometheus.Metric {
+	fetched := collected
+	if group, ok := e.groupDescs[m.Desc()]; ok {
+		e.fetchedMutex.Lock()
+		if t, ok := e.groupFetched[group]; ok {
+			fetched = t
+		}
+		e.fetchedMutex.Unlock()
+	}
+	return prometheus.NewMetricWithTimestamp(fetched, m)
+}
@@ -0,0 +1,43 @@
+package collector
+
+// defaultScrapeErrorWindow is the number of scrapes the error ratio is computed over
+const defaultScrapeErrorWindow = 10
+
+// scrapeWindow is a ring buffer of the outcomes of the most recent scrapes
+type scrapeWindow struct {
+	failed []bool
+	next   int
+	filled int
+}
+
+// newScrapeWindow creates a window of the given number of scrapes
+func newScrapeWindow(size int) *scrapeWindow {
+	if size <= 0 {
+		size = defaultScrapeErrorWindow
+	}
+	return &scrapeWindow{failed: make([]bool, size)}
+}
+
+// record adds the outcome of a scrape, dropping the oldest one when the window is full
+func (w *scrapeWindow) record(failed bool) {
+	w.failed[w.next] = failed
+	w.next = (w.next + 1) % len(w.failed)
+	if w.filled < len(w.failed) {
+		w.filled++
+	}
+}
+
+// errorRatio returns the share of failed scrapes in the window, 0 if it is empty
+func (w *scrapeWindow) errorRatio() float64 {
+	if w.filled == 0 {
+		return 0
+	}
+
+	count := 0
+	for i := 0; i < w.filled; i++ {
+		if w.failed[i] {
+			count++
+		}
+	}
+	return float64(count) / float64(w.filled)
+}
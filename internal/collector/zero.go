@@ -0,0 +1,32 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// zeroDropMetrics are the metric families whose zero-valued series may be dropped.
+// They are per-resource breakdowns where a zero carries no information beyond the
+// absence of the resource. Status, health and scrape metrics are deliberately not
+// listed, as their zero values are alerting signals. This includes
+// pskz_cloud_instance_info, whose key="status" series is 0 for stopped instances.
+var zeroDropMetrics = map[string]bool{
+	"pskz_cloud_quota":      true,
+	"pskz_cloud_summary":    true,
+	"pskz_domain_counters":  true,
+	"pskz_invoice_amount":   true,
+	"pskz_invoice_counters": true,
+}
+
+// dropZero reports whether m is a zero-valued gauge of a family listed in zeroDropMetrics
+func dropZero(m prometheus.Metric) bool {
+	if !zeroDropMetrics[MetricName(m.Desc())] {
+		return false
+	}
+
+	var pb dto.Metric
+	if err := m.Write(&pb); err != nil || pb.Gauge == nil {
+		return false
+	}
+	return pb.GetGauge().GetValue() == 0
+}
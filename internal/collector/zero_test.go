@@ -0,0 +1,29 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestDropZero(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels []string
+		value  float64
+		want   bool
+	}{
+		{"pskz_cloud_quota", []string{"cores"}, 0, true},
+		{"pskz_cloud_quota", []string{"cores"}, 4, false},
+		// A stopped instance has status 0, which must stay visible to alerts
+		{"pskz_cloud_instance_info", []string{"status"}, 0, false},
+		{"pskz_scrape_success", []string{"x"}, 0, false},
+	}
+	for _, tt := range tests {
+		desc := prometheus.NewDesc(tt.name, "test", []string{"key"}, nil)
+		m := prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, tt.value, tt.labels...)
+		if got := dropZero(m); got != tt.want {
+			t.Errorf("dropZero(%s{key=%q} %v) = %v, want %v", tt.name, tt.labels[0], tt.value, got, tt.want)
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 	"gopkg.in/yaml.v3"
@@ -9,33 +12,139 @@ import (
 
 // Config represents the application configuration
 type Config struct {
-	Token     string    `yaml:"token" env:"PSCLOUD_TOKEN,PS_ACCOUNT_TOKEN"`
-	ServiceID string    `yaml:"serviceId" env:"PSCLOUD_SERVICE_ID"`
-	BaseURL   string    `yaml:"baseUrl" env:"PSCLOUD_BASE_URL"`
-	Web       WebConfig `yaml:"web"`
+	Token          string               `yaml:"token" env:"PSCLOUD_TOKEN,PS_ACCOUNT_TOKEN"`
+	TokenSource    string               `yaml:"-"`
+	TokenFile      string               `yaml:"tokenFile" env:"PSCLOUD_TOKEN_FILE"`
+	ServiceID      string               `yaml:"serviceId" env:"PSCLOUD_SERVICE_ID"`
+	BaseURL        string               `yaml:"baseUrl" env:"PSCLOUD_BASE_URL"`
+	AuthHeaderMode string               `yaml:"authHeaderMode" env:"PSCLOUD_AUTH_HEADER_MODE"`
+	DNSZones       []string             `yaml:"dnsZones" env:"PSCLOUD_DNS_ZONES"`
+	Domains        DomainsConfig        `yaml:"domains"`
+	InstanceFields []string             `yaml:"cloudInstanceFields" env:"PSCLOUD_CLOUD_INSTANCE_FIELDS"`
+	Monitor        MonitorConfig        `yaml:"monitor"`
+	ProjectLabel   string               `yaml:"projectLabel" env:"PSCLOUD_PROJECT_LABEL"`
+	Web            WebConfig            `yaml:"web"`
+	Debounce       map[string]int       `yaml:"debounce"`
+	Relabel        []RelabelConfig      `yaml:"relabel"`
+	MaxSeries      int                  `yaml:"maxSeriesPerMetric" env:"PSCLOUD_MAX_SERIES_PER_METRIC"`
+	DropZeroSeries bool                 `yaml:"dropZeroSeries" env:"PSCLOUD_DROP_ZERO_SERIES"`
+	Timestamps     bool                 `yaml:"metricTimestamps" env:"PSCLOUD_METRIC_TIMESTAMPS"`
+	ErrorWindow    int                  `yaml:"scrapeErrorWindow" env:"PSCLOUD_SCRAPE_ERROR_WINDOW"`
+	SuccessPolicy  string               `yaml:"successPolicy" env:"PSCLOUD_SUCCESS_POLICY"`
+	Pagination     PaginationConfig     `yaml:"pagination"`
+	CircuitBreaker CircuitBreakerConfig `yaml:"circuitBreaker"`
+	Services       ServicesConfig       `yaml:"services"`
+	Collectors     CollectorsConfig     `yaml:"collectors"`
+	RegionNames    map[string]string    `yaml:"regionNames"`
+}
+
+// Token sources, in order of precedence
+const (
+	TokenSourceFlag   = "flag"
+	TokenSourceEnv    = "env"
+	TokenSourceConfig = "config"
+	TokenSourceFile   = "token_file"
+)
+
+// DomainsConfig controls which domains are fetched
+type DomainsConfig struct {
+	Statuses []string `yaml:"statuses" env:"PSCLOUD_DOMAIN_STATUSES"`
+	// ExpiryBuckets are the windows in days pskz_domains_expiring_within_count is reported for
+	ExpiryBuckets []int `yaml:"expiryBuckets" env:"PSCLOUD_DOMAIN_EXPIRY_BUCKETS"`
+}
+
+// MonitorConfig limits the VPS and cloud instance metrics to specific resources
+type MonitorConfig struct {
+	VPSServers     []VPSServerConfig `yaml:"vpsServers"`
+	CloudInstances []string          `yaml:"cloudInstances" env:"PSCLOUD_MONITOR_CLOUD_INSTANCES"`
+}
+
+// VPSServerConfig identifies a monitored VPS server
+type VPSServerConfig struct {
+	ServerID int    `yaml:"serverId"`
+	RegionID string `yaml:"regionId"`
+}
+
+// RelabelConfig is a rule that changes the labels of exposed series.
+// Action is rename, drop or lowercase.
+type RelabelConfig struct {
+	Source string `yaml:"source"`
+	Target string `yaml:"target"`
+	Action string `yaml:"action"`
+}
+
+// PaginationConfig controls the page size of paginated API queries
+type PaginationConfig struct {
+	DefaultPerPage int            `yaml:"defaultPerPage" env:"PSCLOUD_DEFAULT_PER_PAGE"`
+	PerPage        map[string]int `yaml:"perPage"`
+}
+
+// CircuitBreakerConfig controls when failing API endpoints are skipped
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that open the circuit; negative disables it
+	FailureThreshold int `yaml:"failureThreshold" env:"PSCLOUD_CIRCUIT_FAILURE_THRESHOLD"`
+	// CooldownSeconds is how long an open circuit skips its endpoint before probing it again
+	CooldownSeconds int `yaml:"cooldownSeconds" env:"PSCLOUD_CIRCUIT_COOLDOWN_SECONDS"`
+}
+
+// ServicesConfig selects the PS.KZ services the exporter calls
+type ServicesConfig struct {
+	// Disabled services are never called and their collectors are skipped
+	Disabled []string `yaml:"disabled" env:"PSCLOUD_DISABLED_SERVICES"`
+}
+
+// CollectorsConfig enables optional collectors
+type CollectorsConfig struct {
+	AccountVerification bool `yaml:"accountVerification"`
+	BankCards           bool `yaml:"bankCards"`
+	DomainPrices        bool `yaml:"domainPrices"`
+	DomainAutoRenew     bool `yaml:"domainAutoRenew"`
 }
 
 // WebConfig represents the web server configuration
 type WebConfig struct {
 	ListenAddress string `yaml:"listenAddress" env:"WEB_LISTEN_ADDRESS"`
+	ListenNetwork string `yaml:"listenNetwork" env:"WEB_LISTEN_NETWORK"`
 	MetricsPrefix string `yaml:"metricsPrefix" env:"WEB_METRICS_PREFIX"`
 	TelemetryPath string `yaml:"telemetryPath" env:"WEB_TELEMETRY_PATH"`
+	// ReadinessGraceSeconds is how long scrapes may keep failing before /readyz reports not ready
+	ReadinessGraceSeconds int `yaml:"readinessGraceSeconds" env:"WEB_READINESS_GRACE_SECONDS"`
 }
 
-// LoadConfig loads the configuration from a YAML file and environment variables
-func LoadConfig(configPath string) (*Config, error) {
+// LoadConfig loads the configuration from a YAML file and environment variables.
+// envFiles are dotenv files loaded before the implicit .env and .env.local;
+// when none are given, the comma-separated PSCLOUD_ENV_FILE list is used.
+func LoadConfig(configPath string, envFiles ...string) (*Config, error) {
 	config := &Config{
-		BaseURL: "https://console.ps.kz",
+		BaseURL:        "https://console.ps.kz",
+		AuthHeaderMode: "both",
+		ProjectLabel:   "domain-id",
+		MaxSeries:      10000,
+		ErrorWindow:    10,
+		SuccessPolicy:  "any",
+		CircuitBreaker: CircuitBreakerConfig{
+			FailureThreshold: 5,
+			CooldownSeconds:  300,
+		},
 		Web: WebConfig{
-			ListenAddress: ":9116",
-			MetricsPrefix: "pskz",
-			TelemetryPath: "/metrics",
+			ListenAddress:         ":9116",
+			ListenNetwork:         "tcp",
+			MetricsPrefix:         "pskz",
+			TelemetryPath:         "/metrics",
+			ReadinessGraceSeconds: 300,
 		},
 	}
 
+	// Load explicitly requested .env files, these must exist
+	if len(envFiles) == 0 {
+		envFiles = getEnvListOrDefault("PSCLOUD_ENV_FILE", nil)
+	}
+	if err := loadEnvFiles(envFiles); err != nil {
+		return nil, err
+	}
+
 	// Load .env file if it exists
-	envFiles := []string{".env", ".env.local"}
-	for _, envFile := range envFiles {
+	for _, envFile := range []string{".env", ".env.local"} {
 		if _, err := os.Stat(envFile); err == nil {
 			if err := godotenv.Load(envFile); err != nil {
 				return nil, err
@@ -56,18 +165,82 @@ func LoadConfig(configPath string) (*Config, error) {
 	}
 
 	// Override with environment variables
-	config.Token = getEnvToken(config.Token)
+	if config.Token != "" {
+		config.TokenSource = TokenSourceConfig
+	}
+	if envToken := getEnvToken(""); envToken != "" {
+		config.Token = envToken
+		config.TokenSource = TokenSourceEnv
+	}
+	config.TokenFile = getEnvOrDefault("PSCLOUD_TOKEN_FILE", config.TokenFile)
 	config.ServiceID = getEnvOrDefault("PSCLOUD_SERVICE_ID", config.ServiceID)
 	config.BaseURL = getEnvOrDefault("PSCLOUD_BASE_URL", config.BaseURL)
+	config.AuthHeaderMode = getEnvOrDefault("PSCLOUD_AUTH_HEADER_MODE", config.AuthHeaderMode)
+	config.DNSZones = getEnvListOrDefault("PSCLOUD_DNS_ZONES", config.DNSZones)
+	config.Services.Disabled = getEnvListOrDefault("PSCLOUD_DISABLED_SERVICES", config.Services.Disabled)
+	config.Domains.Statuses = getEnvListOrDefault("PSCLOUD_DOMAIN_STATUSES", config.Domains.Statuses)
+	expiryBuckets, err := getEnvIntListOrDefault("PSCLOUD_DOMAIN_EXPIRY_BUCKETS", config.Domains.ExpiryBuckets)
+	if err != nil {
+		return nil, err
+	}
+	config.Domains.ExpiryBuckets = expiryBuckets
+	config.InstanceFields = getEnvListOrDefault("PSCLOUD_CLOUD_INSTANCE_FIELDS", config.InstanceFields)
+	config.Monitor.CloudInstances = getEnvListOrDefault("PSCLOUD_MONITOR_CLOUD_INSTANCES", config.Monitor.CloudInstances)
+	config.ProjectLabel = getEnvOrDefault("PSCLOUD_PROJECT_LABEL", config.ProjectLabel)
+	config.MaxSeries = getEnvIntOrDefault("PSCLOUD_MAX_SERIES_PER_METRIC", config.MaxSeries)
+	config.DropZeroSeries = getEnvBoolOrDefault("PSCLOUD_DROP_ZERO_SERIES", config.DropZeroSeries)
+	config.Timestamps = getEnvBoolOrDefault("PSCLOUD_METRIC_TIMESTAMPS", config.Timestamps)
+	config.ErrorWindow = getEnvIntOrDefault("PSCLOUD_SCRAPE_ERROR_WINDOW", config.ErrorWindow)
+	config.SuccessPolicy = getEnvOrDefault("PSCLOUD_SUCCESS_POLICY", config.SuccessPolicy)
+	config.Pagination.DefaultPerPage = getEnvIntOrDefault("PSCLOUD_DEFAULT_PER_PAGE", config.Pagination.DefaultPerPage)
+	config.CircuitBreaker.FailureThreshold = getEnvIntOrDefault("PSCLOUD_CIRCUIT_FAILURE_THRESHOLD", config.CircuitBreaker.FailureThreshold)
+	config.CircuitBreaker.CooldownSeconds = getEnvIntOrDefault("PSCLOUD_CIRCUIT_COOLDOWN_SECONDS", config.CircuitBreaker.CooldownSeconds)
 
 	// Web configuration
 	config.Web.ListenAddress = getEnvOrDefault("WEB_LISTEN_ADDRESS", config.Web.ListenAddress)
+	config.Web.ListenNetwork = getEnvOrDefault("WEB_LISTEN_NETWORK", config.Web.ListenNetwork)
 	config.Web.MetricsPrefix = getEnvOrDefault("WEB_METRICS_PREFIX", config.Web.MetricsPrefix)
 	config.Web.TelemetryPath = getEnvOrDefault("WEB_TELEMETRY_PATH", config.Web.TelemetryPath)
+	config.Web.ReadinessGraceSeconds = getEnvIntOrDefault("WEB_READINESS_GRACE_SECONDS", config.Web.ReadinessGraceSeconds)
 
 	return config, nil
 }
 
+// loadEnvFiles loads the given dotenv files in order, later files overriding earlier ones.
+// Variables already set in the process environment are never overridden.
+func loadEnvFiles(paths []string) error {
+	values := make(map[string]string)
+	for _, path := range paths {
+		fileValues, err := godotenv.Read(path)
+		if err != nil {
+			return fmt.Errorf("failed to load env file %s: %w", path, err)
+		}
+		for key, value := range fileValues {
+			values[key] = value
+		}
+	}
+
+	for key, value := range values {
+		if _, exists := os.LookupEnv(key); exists {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReadTokenFile reads the API token from the given file, trimming surrounding whitespace
+func ReadTokenFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
 // getEnvToken checks for token in environment variables
 // First checks PS_ACCOUNT_TOKEN, then falls back to PSCLOUD_TOKEN
 func getEnvToken(defaultValue string) string {
@@ -91,3 +264,53 @@ func getEnvOrDefault(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvListOrDefault reads a comma-separated list from the environment
+func getEnvListOrDefault(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getEnvIntListOrDefault parses a comma-separated list of integers from the environment
+func getEnvIntListOrDefault(key string, defaultValue []int) ([]int, error) {
+	items := getEnvListOrDefault(key, nil)
+	if items == nil {
+		return defaultValue, nil
+	}
+
+	values := make([]int, 0, len(items))
+	for _, item := range items {
+		value, err := strconv.Atoi(item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %q is not an integer", key, item)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// getEnvBoolOrDefault parses a boolean such as "true" or "1" from the environment
+func getEnvBoolOrDefault(key string, defaultValue bool) bool {
+	if value, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvIntOrDefault reads an integer from the environment, ignoring invalid values
+func getEnvIntOrDefault(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}